@@ -0,0 +1,75 @@
+package regorm
+
+import (
+	"context"
+	"reflect"
+)
+
+// WithActor returns a copy of the repository stamping actorID as the author of the changes it makes,
+// Create, CreateOmit, BatchCreate, FirstOrCreate and Upsert set the CreatedBy field and Update and UpdateOmit
+// set the UpdatedBy field of the models having them. The original repository is left untouched:
+//
+//	repo.WithActor(currentUser.ID).Create(ctx, &post)
+func (r *Repository[T]) WithActor(actorID interface{}) IRepository[T] {
+	clone := *r
+	clone.actor = actorID
+
+	return &clone
+}
+
+// prepareCreate stamps the actor, tenant and timestamps of repository into models before inserting them
+func (r *Repository[T]) prepareCreate(ctx context.Context, models []*T) error {
+	if err := r.stampTenant(ctx, models); err != nil {
+		return err
+	}
+
+	if err := r.stampTimestamps(ctx, true, models); err != nil {
+		return err
+	}
+
+	if r.actor == nil {
+		return nil
+	}
+
+	return r.stamp(ctx, "CreatedBy", r.actor, models)
+}
+
+// prepareUpdate stamps the actor, tenant and timestamps of repository into models before saving them
+func (r *Repository[T]) prepareUpdate(ctx context.Context, models []*T) error {
+	if err := r.stampTenant(ctx, models); err != nil {
+		return err
+	}
+
+	if err := r.stampTimestamps(ctx, false, models); err != nil {
+		return err
+	}
+
+	if r.actor == nil {
+		return nil
+	}
+
+	return r.stamp(ctx, "UpdatedBy", r.actor, models)
+}
+
+// stamp sets the field named name of models to value, models without such field are left untouched
+func (r *Repository[T]) stamp(ctx context.Context, name string, value interface{}, models []*T) error {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return err
+	}
+
+	field := s.LookUpField(name)
+
+	if field == nil || field.DBName == "" {
+		return nil
+	}
+
+	for _, model := range models {
+		if err := field.Set(ctx, reflect.ValueOf(model).Elem(), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+)
+
+type Audit struct {
+	ID        uint
+	Name      string
+	CreatedBy uint
+	UpdatedBy uint
+}
+
+func (Audit) TableName() string { return "audits" }
+
+func TestWithActor(t *testing.T) {
+	repo := InitRepository[Audit](openDB(t, &Audit{}))
+	ctx := context.Background()
+	record := &Audit{Name: "a"}
+
+	if _, err := repo.WithActor(uint(7)).Create(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := &Audit{Name: "b"}
+
+	if _, err := repo.Create(ctx, plain); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Name = "changed"
+
+	if err := repo.WithActor(uint(9)).Update(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored Audit
+
+	if err := repo.First(ctx, &stored, record.ID); err != nil || stored.CreatedBy != 7 || stored.UpdatedBy != 9 {
+		t.Fatalf("got %+v, %v, want created by 7 and updated by 9", stored, err)
+	}
+
+	stored = Audit{}
+
+	if err := repo.First(ctx, &stored, plain.ID); err != nil || stored.CreatedBy != 0 || stored.UpdatedBy != 0 {
+		t.Fatalf("got %+v, %v, want no actor stamped", stored, err)
+	}
+
+	if _, err := InitRepository[User](openDB(t)).WithActor(uint(7)).Create(ctx, &User{Name: "alice"}); err != nil {
+		t.Fatalf("got %v, want models without actor fields unaffected", err)
+	}
+}
@@ -0,0 +1,93 @@
+package regorm
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm/clause"
+)
+
+// Sum returns the sum of column over the records matching given conditions,
+// returns 0 if no record matches. column is validated against the model schema.
+func (r *Repository[T]) Sum(ctx context.Context, column string, conds ...interface{}) (float64, error) {
+	return r.aggregate(ctx, "Sum", "SUM", column, conds)
+}
+
+// Avg returns the average of column over the records matching given conditions,
+// returns 0 if no record matches. column is validated against the model schema.
+func (r *Repository[T]) Avg(ctx context.Context, column string, conds ...interface{}) (float64, error) {
+	return r.aggregate(ctx, "Avg", "AVG", column, conds)
+}
+
+// Min returns the minimum of column over the records matching given conditions,
+// returns 0 if no record matches. column is validated against the model schema.
+func (r *Repository[T]) Min(ctx context.Context, column string, conds ...interface{}) (float64, error) {
+	return r.aggregate(ctx, "Min", "MIN", column, conds)
+}
+
+// Max returns the maximum of column over the records matching given conditions,
+// returns 0 if no record matches. column is validated against the model schema.
+func (r *Repository[T]) Max(ctx context.Context, column string, conds ...interface{}) (float64, error) {
+	return r.aggregate(ctx, "Max", "MAX", column, conds)
+}
+
+// GroupCount counts the records matching given conditions per value of groupColumn,
+// groups are keyed by the string form of the value and null values are keyed by "".
+// groupColumn is validated against the model schema.
+func (r *Repository[T]) GroupCount(ctx context.Context, groupColumn string, conds ...interface{}) (map[string]int64, error) {
+	var groups []struct {
+		GroupValue sql.NullString
+		GroupCount int64
+	}
+	err := r.run(ctx, "GroupCount", func(ctx context.Context) (int64, error) {
+		name, err := r.column(groupColumn)
+
+		if err != nil {
+			return 0, err
+		}
+
+		column := clause.Column{Table: clause.CurrentTable, Name: name}
+		res := r.query(ctx, conds).
+			Select("? AS group_value, COUNT(*) AS group_count", column).
+			Group(name).
+			Scan(&groups)
+
+		return res.RowsAffected, res.Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(groups))
+
+	for _, group := range groups {
+		result[group.GroupValue.String] += group.GroupCount
+	}
+
+	return result, nil
+}
+
+// aggregate runs SELECT COALESCE(fn(column), 0) over the records matching conds as operation op
+func (r *Repository[T]) aggregate(ctx context.Context, op string, fn string, column string, conds []interface{}) (float64, error) {
+	var result float64
+	err := r.run(ctx, op, func(ctx context.Context) (int64, error) {
+		name, err := r.column(column)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.query(ctx, conds).
+			Select("COALESCE("+fn+"(?), 0)", clause.Column{Table: clause.CurrentTable, Name: name}).
+			Scan(&result)
+
+		return res.RowsAffected, res.Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
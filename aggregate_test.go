@@ -0,0 +1,109 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// seedProducts inserts products priced 10, 20, 30 and 40, the first two coded cheap
+func seedProducts(t *testing.T, repo IRepository[Product]) {
+	t.Helper()
+
+	products := []*Product{
+		{Code: "cheap-1", Price: 10},
+		{Code: "cheap-2", Price: 20},
+		{Code: "mid", Price: 30},
+		{Code: "pricey", Price: 40},
+	}
+
+	if _, err := repo.BatchCreate(context.Background(), products); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSum(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+
+	if sum, err := repo.Sum(ctx, "price"); err != nil || sum != 0 {
+		t.Fatalf("got %v, %v, want 0 on an empty table", sum, err)
+	}
+
+	seedProducts(t, repo)
+
+	if sum, err := repo.Sum(ctx, "price", "code LIKE ?", "cheap-%"); err != nil || sum != 30 {
+		t.Fatalf("got %v, %v, want 30", sum, err)
+	}
+
+	if sum, err := repo.Sum(ctx, "price", "code = ?", "missing"); err != nil || sum != 0 {
+		t.Fatalf("got %v, %v, want 0 when nothing matches", sum, err)
+	}
+
+	if _, err := repo.Sum(ctx, "price) FROM products; --"); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestAvgMinMax(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+
+	for name, aggregate := range map[string]func(context.Context, string, ...interface{}) (float64, error){
+		"Avg": repo.Avg,
+		"Min": repo.Min,
+		"Max": repo.Max,
+	} {
+		if value, err := aggregate(ctx, "price"); err != nil || value != 0 {
+			t.Fatalf("%s: got %v, %v, want 0 on an empty table", name, value, err)
+		}
+
+		if _, err := aggregate(ctx, "missing"); !errors.Is(err, ErrInvalidColumn) {
+			t.Fatalf("%s: got %v, want ErrInvalidColumn", name, err)
+		}
+	}
+
+	seedProducts(t, repo)
+
+	if avg, err := repo.Avg(ctx, "price"); err != nil || avg != 25 {
+		t.Fatalf("Avg: got %v, %v, want 25", avg, err)
+	}
+
+	if low, err := repo.Min(ctx, "price", "price > ?", 10); err != nil || low != 20 {
+		t.Fatalf("Min: got %v, %v, want 20", low, err)
+	}
+
+	if high, err := repo.Max(ctx, "price", "code LIKE ?", "cheap-%"); err != nil || high != 20 {
+		t.Fatalf("Max: got %v, %v, want 20", high, err)
+	}
+}
+
+func TestGroupCount(t *testing.T) {
+	repo := InitRepository[Purchase](openDB(t, &Purchase{}))
+	ctx := context.Background()
+
+	purchases := []*Purchase{{Status: "paid"}, {Status: "pending"}, {Status: "paid"}, {Status: "refunded"}, {Status: "paid"}}
+
+	if _, err := repo.BatchCreate(ctx, purchases); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := repo.GroupCount(ctx, "status")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(counts, map[string]int64{"paid": 3, "pending": 1, "refunded": 1}) {
+		t.Fatalf("got %v, want the count per status", counts)
+	}
+
+	if counts, err = repo.GroupCount(ctx, "status", "status <> ?", "paid"); err != nil || len(counts) != 2 {
+		t.Fatalf("got %v, %v, want the conditions applied", counts, err)
+	}
+
+	if _, err := repo.GroupCount(ctx, "missing"); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
@@ -0,0 +1,122 @@
+package regorm
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// CountAssociation counts the records related to value through association, like the orders of an user:
+//
+//	count, err := repo.CountAssociation(ctx, &user, "Orders")
+//
+// returns ErrInvalidAssociation if the model has no such association
+func (r *Repository[T]) CountAssociation(ctx context.Context, model *T, association string) (int64, error) {
+	var count int64
+	err := r.run(ctx, "CountAssociation", func(ctx context.Context) (int64, error) {
+		assoc, err := r.association(r.associationDB(ctx), model, association)
+
+		if err != nil {
+			return 0, err
+		}
+
+		count = assoc.Count()
+
+		return 0, assoc.Error
+	})
+
+	return count, err
+}
+
+// FindAssociated loads the records related to value through association into dest,
+// many to many associations are queried through their join table:
+//
+//	var roles []Role
+//	err := repo.FindAssociated(ctx, &user, "Roles", &roles)
+//
+// returns ErrInvalidAssociation if the model has no such association
+func (r *Repository[T]) FindAssociated(ctx context.Context, model *T, association string, dest interface{}) error {
+	return r.run(ctx, "FindAssociated", func(ctx context.Context) (int64, error) {
+		assoc, err := r.association(r.associationDB(ctx), model, association)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, assoc.Find(dest)
+	})
+}
+
+// AppendAssociation adds values to the records related to value through association,
+// for many to many associations the join table rows are inserted along with the new related records:
+//
+//	repo.AppendAssociation(ctx, &user, "Roles", &admin, &editor)
+//
+// returns ErrInvalidAssociation if the model has no such association
+func (r *Repository[T]) AppendAssociation(ctx context.Context, model *T, association string, values ...interface{}) error {
+	return r.run(ctx, "AppendAssociation", func(ctx context.Context) (int64, error) {
+		return 0, r.inAssociation(ctx, model, association, func(assoc *gorm.Association) error {
+			return assoc.Append(values...)
+		})
+	})
+}
+
+// ReplaceAssociation replaces the records related to value through association with values,
+// the links to the previous related records are removed but the records themselves aren't deleted.
+// returns ErrInvalidAssociation if the model has no such association
+func (r *Repository[T]) ReplaceAssociation(ctx context.Context, model *T, association string, values ...interface{}) error {
+	return r.run(ctx, "ReplaceAssociation", func(ctx context.Context) (int64, error) {
+		return 0, r.inAssociation(ctx, model, association, func(assoc *gorm.Association) error {
+			return assoc.Replace(values...)
+		})
+	})
+}
+
+// DeleteAssociation removes the links between value and values through association,
+// for many to many associations the join table rows are deleted but the related records aren't.
+// returns ErrInvalidAssociation if the model has no such association
+func (r *Repository[T]) DeleteAssociation(ctx context.Context, model *T, association string, values ...interface{}) error {
+	return r.run(ctx, "DeleteAssociation", func(ctx context.Context) (int64, error) {
+		return 0, r.inAssociation(ctx, model, association, func(assoc *gorm.Association) error {
+			return assoc.Delete(values...)
+		})
+	})
+}
+
+// inAssociation runs fn with the association mode of value in a transaction,
+// as association writes run several statements
+func (r *Repository[T]) inAssociation(ctx context.Context, model *T, association string, fn func(assoc *gorm.Association) error) error {
+	return r.associationDB(ctx).Transaction(func(tx *gorm.DB) error {
+		assoc, err := r.association(tx, model, association)
+
+		if err != nil {
+			return err
+		}
+
+		return fn(assoc)
+	})
+}
+
+// association returns the association mode of value on db, the association is validated against the model schema
+func (r *Repository[T]) association(db *gorm.DB, model *T, name string) (*gorm.Association, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.Relationships.Relations[name]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAssociation, name)
+	}
+
+	assoc := db.Model(model).Association(name)
+
+	return assoc, assoc.Error
+}
+
+// associationDB returns the database handle bound to ctx for association methods, it isn't scoped
+// by WithTenant or SetSoftDeleteColumn as these apply to the repository model, not to the related ones
+func (r *Repository[T]) associationDB(ctx context.Context) *gorm.DB {
+	return r.resolve(ctx, r.Database.WithContext(ctx))
+}
@@ -0,0 +1,129 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Member has many roles through the user_roles join table
+type Member struct {
+	ID    uint
+	Name  string
+	Roles []Role `gorm:"many2many:user_roles"`
+}
+
+func (Member) TableName() string { return "members" }
+
+type Role struct {
+	ID   uint
+	Name string
+}
+
+func (Role) TableName() string { return "roles" }
+
+// links returns the role ids of the user_roles rows of member
+func links(t *testing.T, repo IRepository[Member], member uint) []uint {
+	t.Helper()
+
+	var ids []uint
+
+	if err := repo.GetDB().Table("user_roles").Where("member_id = ?", member).Order("role_id").Pluck("role_id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return ids
+}
+
+func TestCountAssociation(t *testing.T) {
+	repo := InitRepository[Customer](openDB(t, &Customer{}, &Purchase{}, &Item{}))
+	ctx := context.Background()
+	seedCustomers(t, repo)
+
+	alice, bob := Customer{ID: 1}, Customer{ID: 2}
+
+	if count, err := repo.CountAssociation(ctx, &alice, "Orders"); err != nil || count != 2 {
+		t.Fatalf("got %d, %v, want 2 orders", count, err)
+	}
+
+	if count, err := repo.CountAssociation(ctx, &bob, "Orders"); err != nil || count != 0 {
+		t.Fatalf("got %d, %v, want no orders", count, err)
+	}
+
+	if _, err := repo.CountAssociation(ctx, &alice, "Invoices"); !errors.Is(err, ErrInvalidAssociation) {
+		t.Fatalf("got %v, want ErrInvalidAssociation", err)
+	}
+}
+
+func TestAppendDeleteAssociation(t *testing.T) {
+	repo := InitRepository[Member](openDB(t, &Member{}, &Role{}))
+	ctx := context.Background()
+	member := &Member{Name: "alice"}
+
+	if _, err := repo.Create(ctx, member); err != nil {
+		t.Fatal(err)
+	}
+
+	admin, editor, viewer := &Role{Name: "admin"}, &Role{Name: "editor"}, &Role{Name: "viewer"}
+
+	if err := repo.AppendAssociation(ctx, member, "Roles", admin, editor); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := links(t, repo, member.ID); len(ids) != 2 || ids[0] != admin.ID || ids[1] != editor.ID {
+		t.Fatalf("got %v, want admin and editor linked", ids)
+	}
+
+	if err := repo.DeleteAssociation(ctx, member, "Roles", admin); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := links(t, repo, member.ID); len(ids) != 1 || ids[0] != editor.ID {
+		t.Fatalf("got %v, want only editor linked", ids)
+	}
+
+	var roles int64
+
+	if err := repo.GetDB().Model(&Role{}).Count(&roles).Error; err != nil || roles != 2 {
+		t.Fatalf("got %d, %v, want the unlinked role kept", roles, err)
+	}
+
+	if err := repo.ReplaceAssociation(ctx, member, "Roles", viewer); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := links(t, repo, member.ID); len(ids) != 1 || ids[0] != viewer.ID {
+		t.Fatalf("got %v, want only viewer linked", ids)
+	}
+
+	if err := repo.AppendAssociation(ctx, member, "Groups", admin); !errors.Is(err, ErrInvalidAssociation) {
+		t.Fatalf("got %v, want ErrInvalidAssociation", err)
+	}
+}
+
+func TestFindAssociated(t *testing.T) {
+	repo := InitRepository[Member](openDB(t, &Member{}, &Role{}))
+	ctx := context.Background()
+	members := []*Member{
+		{Name: "alice", Roles: []Role{{Name: "admin"}, {Name: "editor"}}},
+		{Name: "bob", Roles: []Role{{Name: "viewer"}}},
+	}
+
+	if _, err := repo.BatchCreate(ctx, members); err != nil {
+		t.Fatal(err)
+	}
+
+	var roles []Role
+
+	if err := repo.FindAssociated(ctx, &Member{ID: members[0].ID}, "Roles", &roles); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(roles) != 2 || roles[0].Name != "admin" || roles[1].Name != "editor" {
+		t.Fatalf("got %+v, want the roles of alice", roles)
+	}
+
+	if err := repo.FindAssociated(ctx, &Member{ID: members[0].ID}, "Groups", &roles); !errors.Is(err, ErrInvalidAssociation) {
+		t.Fatalf("got %v, want ErrInvalidAssociation", err)
+	}
+}
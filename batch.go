@@ -0,0 +1,112 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FindInBatches finds the records matching given conditions batchSize records at a time
+// and calls fn for each batch, so the whole result never lives in memory at once.
+// Processing stops at the first error returned by fn, which is returned as is.
+// batchSize <= 0 falls back to DefaultPageSize.
+func (r *Repository[T]) FindInBatches(ctx context.Context, batchSize int, fn func(batch []T) error, conds ...interface{}) error {
+	_, batchSize = normalizePage(DefaultPage, batchSize)
+
+	return r.run(ctx, "FindInBatches", func(ctx context.Context) (int64, error) {
+		var batch []T
+		res := r.query(ctx, conds).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			if err := r.decrypt(ctx, &batch); err != nil {
+				return err
+			}
+
+			return fn(batch)
+		})
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// Stream streams the records matching given conditions one at a time, reading them lazily from
+// the database. The records are sent ordered by primary key unless conds order them. Both channels are closed when all the records are sent, the error channel carries
+// at most one error which ends the stream. Stream stops when ctx is done, so callers which stop
+// reading before the stream ends should cancel ctx to release the underlying rows.
+//
+//	items, errs := repo.Stream(ctx, "status = ?", "active")
+//	for item := range items {
+//		...
+//	}
+//	if err := <-errs; err != nil {
+//		...
+//	}
+func (r *Repository[T]) Stream(ctx context.Context, conds ...interface{}) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+
+		err := r.run(ctx, "Stream", func(ctx context.Context) (int64, error) {
+			db := r.query(ctx, conds).Scopes(orderedByPrimaryKey)
+			rows, err := db.Rows()
+
+			if err != nil {
+				return 0, err
+			}
+
+			defer rows.Close()
+
+			var count int64
+
+			for rows.Next() {
+				var item T
+
+				if err := db.ScanRows(rows, &item); err != nil {
+					return count, err
+				}
+
+				if err := r.decrypt(ctx, &item); err != nil {
+					return count, err
+				}
+
+				select {
+				case items <- item:
+					count++
+				case <-ctx.Done():
+					return count, ctx.Err()
+				}
+			}
+
+			return count, rows.Err()
+		})
+
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return items, errs
+}
+
+// orderedByPrimaryKey orders db by the primary key of its model unless it's ordered already, it's applied
+// as a scope so the orders of the repository scopes are seen
+func orderedByPrimaryKey(db *gorm.DB) *gorm.DB {
+	if _, ok := db.Statement.Clauses["ORDER BY"]; ok {
+		return db
+	}
+
+	s, err := parseSchema(db, db.Statement.Model)
+
+	if err != nil {
+		_ = db.AddError(err)
+		return db
+	}
+
+	for _, field := range s.PrimaryFields {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}})
+	}
+
+	return db
+}
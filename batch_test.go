@@ -0,0 +1,115 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindInBatches(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seedN(t, repo, 25)
+
+	var sizes []int
+
+	err := repo.FindInBatches(ctx, 10, func(batch []User) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+
+	if err != nil || !reflect.DeepEqual(sizes, []int{10, 10, 5}) {
+		t.Fatalf("got %v, %v, want batches of 10, 10 and 5", sizes, err)
+	}
+
+	sizes = nil
+	err = repo.FindInBatches(ctx, 10, func(batch []User) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	}, "id > ?", 20)
+
+	if err != nil || !reflect.DeepEqual(sizes, []int{5}) {
+		t.Fatalf("got %v, %v, want the conditions applied", sizes, err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.FindInBatches(ctx, 10, func(batch []User) error {
+		calls++
+		return stop
+	})
+
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("got %v after %d calls, want the first error returned", err, calls)
+	}
+}
+
+func TestStream(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	seedN(t, repo, 12)
+	rec := record(t, db)
+
+	items, errs := repo.Stream(context.Background(), "id > ?", 2)
+	var ids []uint
+
+	for item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 10 {
+		t.Fatalf("got %v, want ids 3 to 12", ids)
+	}
+
+	for i, id := range ids {
+		if id != uint(i+3) {
+			t.Fatalf("got %v, want ids 3 to 12 in order", ids)
+		}
+	}
+
+	if sql := rec.last(); !strings.HasSuffix(sql, "ORDER BY `users`.`id`") {
+		t.Fatalf("got %q, want the records ordered by primary key", sql)
+	}
+
+	items, errs = repo.Stream(context.Background(), OrderBy("id", true), "id > ?", 9)
+	ids = nil
+
+	for item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	if err := <-errs; err != nil || !reflect.DeepEqual(ids, []uint{12, 11, 10}) {
+		t.Fatalf("got %v, %v, want the order of the conditions", ids, err)
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	seedN(t, repo, 12)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items, errs := repo.Stream(ctx)
+
+	if item := <-items; item.ID != 1 {
+		t.Fatalf("got %+v, want the first record", item)
+	}
+
+	cancel()
+
+	// nothing reads items meanwhile, so the stream can only end because of the cancellation
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	if _, open := <-items; open {
+		t.Fatal("want items closed")
+	}
+}
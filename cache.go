@@ -0,0 +1,133 @@
+package regorm
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cache is the backend of the query cache of repositories, set it with SetCache.
+// It lets repositories cache their reads in Redis, memcached or any other store:
+//
+//	type redisCache struct{ client *redis.Client }
+//
+//	func (c redisCache) Get(key string) ([]byte, bool) {
+//		val, err := c.client.Get(context.Background(), key).Bytes()
+//		return val, err == nil
+//	}
+//
+//	func (c redisCache) Set(key string, val []byte, ttl time.Duration) {
+//		c.client.Set(context.Background(), key, val, ttl)
+//	}
+type Cache interface {
+	Get(key string) ([]byte, bool)                 // Get the value of key, reports false if key isn't cached
+	Set(key string, val []byte, ttl time.Duration) // Set the value of key, a zero ttl never expires
+}
+
+// PrefixDeleter is implemented by the caches able to delete all the keys starting with a prefix,
+// like with Redis SCAN and DEL. Writes purge the cached queries of the model table from such caches,
+// other caches only make them unreachable and let them expire.
+type PrefixDeleter interface {
+	DeletePrefix(prefix string) // Delete all the keys starting with prefix
+}
+
+// SetCache sets the cache the results of First and Find are served from for ttl, keyed by their SQL.
+// Results are cached as JSON, so fields ignored by encoding/json like `json:"-"` ones aren't cached.
+// Any write through the repository like Create, Update, UpdateWhere, DeleteWhere or Exec invalidates
+// the cached queries of the model table, keys are prefixed with regorm:<table>: to be purged by a PrefixDeleter.
+// Queries in a transaction, locking reads like FirstForUpdate and queries preloading associations bypass
+// the cache, the preloads aren't part of the SQL of the query. A nil cache disables caching.
+func (r *Repository[T]) SetCache(cache Cache, ttl time.Duration) {
+	r.cache = cache
+	r.cacheTTL = ttl
+}
+
+// cachedQuery runs query on db scanning into dest and returns rows affected,
+// the result is served from the cache of repository when it's cached already
+func (r *Repository[T]) cachedQuery(db *gorm.DB, dest interface{}, query func(db *gorm.DB) *gorm.DB) (int64, error) {
+	if r.cache == nil || r.inTransaction() {
+		res := query(db)
+
+		return res.RowsAffected, res.Error
+	}
+
+	stmt := query(db.Session(&gorm.Session{DryRun: true})).Statement
+
+	if stmt.Error != nil {
+		return 0, stmt.Error
+	}
+
+	// the associations are loaded by queries of their own, the cached result would miss them,
+	// and a cached result would take no lock
+	if _, locking := stmt.Clauses[clause.Locking{}.Name()]; locking || len(stmt.Preloads) > 0 {
+		res := query(db)
+
+		return res.RowsAffected, res.Error
+	}
+
+	key := r.cacheKey(stmt.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+
+	if data, ok := r.cache.Get(key); ok {
+		var cached cachedResult
+
+		if err := json.Unmarshal(data, &cached); err == nil && json.Unmarshal(cached.Dest, dest) == nil {
+			return cached.Rows, nil
+		}
+	}
+
+	res := query(db)
+
+	if res.Error != nil {
+		return res.RowsAffected, res.Error
+	}
+
+	if data, err := json.Marshal(dest); err == nil {
+		if data, err := json.Marshal(cachedResult{Rows: res.RowsAffected, Dest: data}); err == nil {
+			r.cache.Set(key, data, r.cacheTTL)
+		}
+	}
+
+	return res.RowsAffected, nil
+}
+
+// cachedResult is a query result stored in the cache
+type cachedResult struct {
+	Rows int64           `json:"rows"`
+	Dest json.RawMessage `json:"dest"`
+}
+
+// cacheKey returns the cache key of the query sql, keys include the generation of the model table
+// so invalidating the table makes the previous keys unreachable
+func (r *Repository[T]) cacheKey(sql string) string {
+	return r.cachePrefix() + r.cacheGeneration() + ":" + sql
+}
+
+// cachePrefix returns the prefix of the cache keys of the model table
+func (r *Repository[T]) cachePrefix() string {
+	return "regorm:" + r.table() + ":"
+}
+
+// cacheGeneration returns the current generation of the cached queries of the model table
+func (r *Repository[T]) cacheGeneration() string {
+	if generation, ok := r.cache.Get(r.cachePrefix() + "generation"); ok {
+		return string(generation)
+	}
+
+	return r.invalidateCache()
+}
+
+// invalidateCache starts a new generation of the cached queries of the model table and returns it,
+// the queries of the previous generations are purged from a PrefixDeleter cache
+func (r *Repository[T]) invalidateCache() string {
+	if deleter, ok := r.cache.(PrefixDeleter); ok {
+		deleter.DeletePrefix(r.cachePrefix())
+	}
+
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	r.cache.Set(r.cachePrefix()+"generation", []byte(generation), 0)
+
+	return generation
+}
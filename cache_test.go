@@ -0,0 +1,245 @@
+package regorm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryCache is an in-memory Cache, ttls are ignored
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: map[string][]byte{}}
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, ok := c.values[key]
+
+	return val, ok
+}
+
+func (c *memoryCache) Set(key string, val []byte, _ time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] = val
+}
+
+// queries returns the number of cached query results
+func (c *memoryCache) queries() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+
+	for key := range c.values {
+		if !strings.HasSuffix(key, ":generation") {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	seed(t, repo, "alice", "bob")
+
+	cache := newMemoryCache()
+	repo.SetCache(cache, time.Minute)
+	rec := record(t, db)
+
+	for i := 0; i < 2; i++ {
+		var user User
+		var users []User
+
+		if err := repo.First(ctx, &user, 2); err != nil || user.Name != "bob" {
+			t.Fatalf("First: got %+v, %v, want bob", user, err)
+		}
+
+		if err := repo.Find(ctx, &users); err != nil || len(users) != 2 {
+			t.Fatalf("Find: got %+v, %v, want 2 users", users, err)
+		}
+	}
+
+	if n := rec.executed("SELECT"); n != 2 {
+		t.Fatalf("got %d queries, want 2 with the second reads served from the cache", n)
+	}
+
+	if n := cache.queries(); n != 2 {
+		t.Fatalf("got %d cached queries, want 2", n)
+	}
+
+	var missing User
+
+	if err := repo.First(ctx, &missing, 3); err != nil || missing.ID != 0 {
+		t.Fatalf("got %+v, %v, want nothing found", missing, err)
+	}
+
+	err := repo.RunInTransaction(ctx, func(txRepo IRepository[User]) error {
+		var user User
+
+		return txRepo.First(ctx, &user, 2)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := rec.executed("SELECT"); n != 4 {
+		t.Fatalf("got %d queries, want the query in a transaction to bypass the cache", n)
+	}
+}
+
+func TestCachePreload(t *testing.T) {
+	ctx := context.Background()
+	repo := InitRepository[Customer](openDB(t, &Customer{}, &Purchase{}, &Item{}))
+	seedCustomers(t, repo)
+	repo.SetCache(newMemoryCache(), time.Minute)
+
+	var customers []Customer
+
+	if err := repo.Find(ctx, &customers, "name = ?", "alice"); err != nil || len(customers) != 1 || len(customers[0].Orders) != 0 {
+		t.Fatalf("got %+v, %v, want alice without her orders", customers, err)
+	}
+
+	customers = nil
+
+	if err := repo.Find(ctx, &customers, Preload("Orders"), "name = ?", "alice"); err != nil || len(customers) != 1 || len(customers[0].Orders) != 2 {
+		t.Fatalf("got %+v, %v, want alice with her 2 orders", customers, err)
+	}
+
+	var customer Customer
+
+	if err := repo.First(ctx, &customer, Preload("Orders"), "name = ?", "alice"); err != nil || len(customer.Orders) != 2 {
+		t.Fatalf("got %+v, %v, want alice with her 2 orders", customer, err)
+	}
+}
+
+func TestCacheLocking(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	users := seed(t, repo, "alice")
+
+	cache := newMemoryCache()
+	repo.SetCache(cache, time.Minute)
+	rec := record(t, db)
+
+	for i := 0; i < 2; i++ {
+		var user User
+
+		if err := repo.FirstForUpdate(ctx, &user, users[0].ID); err != nil || user.Name != "alice" {
+			t.Fatalf("FirstForUpdate: got %+v, %v, want alice", user, err)
+		}
+
+		if err := repo.FirstForShare(ctx, &user, users[0].ID); err != nil || user.Name != "alice" {
+			t.Fatalf("FirstForShare: got %+v, %v, want alice", user, err)
+		}
+	}
+
+	if n := rec.executed("SELECT"); n != 4 {
+		t.Fatalf("got %d queries, want the locking reads to bypass the cache", n)
+	}
+
+	if n := cache.queries(); n != 0 {
+		t.Fatalf("got %d cached queries, want 0", n)
+	}
+}
+
+// prefixCache is a memoryCache able to delete the keys by prefix
+type prefixCache struct {
+	*memoryCache
+}
+
+func (c prefixCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.values, key)
+		}
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	seed(t, repo, "alice")
+
+	cache := prefixCache{newMemoryCache()}
+	repo.SetCache(cache, time.Minute)
+
+	find := func() []User {
+		t.Helper()
+
+		var users []User
+
+		if err := repo.Find(ctx, &users); err != nil {
+			t.Fatal(err)
+		}
+
+		return users
+	}
+
+	if users := find(); len(users) != 1 {
+		t.Fatalf("got %+v, want alice", users)
+	}
+
+	seed(t, repo, "bob")
+
+	if users := find(); len(users) != 2 {
+		t.Fatalf("got %+v, want bob found after Create", users)
+	}
+
+	if _, err := repo.UpdateWhere(ctx, map[string]interface{}{"name": "bob"}, map[string]interface{}{"age": 50}); err != nil {
+		t.Fatal(err)
+	}
+
+	if users := find(); users[1].Age != 50 {
+		t.Fatalf("got %+v, want bob updated after UpdateWhere", users)
+	}
+
+	if _, err := repo.DeleteWhere(ctx, map[string]interface{}{"name": "bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if users := find(); len(users) != 1 {
+		t.Fatalf("got %+v, want bob gone after DeleteWhere", users)
+	}
+
+	if n := cache.queries(); n != 1 {
+		t.Fatalf("got %d cached queries, want the stale ones purged", n)
+	}
+
+	rec := record(t, db)
+
+	if err := repo.FirstForUpdate(ctx, &User{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.FirstForShare(ctx, &User{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if users := find(); len(users) != 1 {
+		t.Fatalf("got %+v, want alice", users)
+	}
+
+	if n := rec.executed("SELECT"); n != 2 {
+		t.Fatalf("got %d queries, want the locking reads to leave the cached Find intact", n)
+	}
+}
@@ -0,0 +1,93 @@
+package regorm
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// Cursor iterates over the records of a query one at a time, reading them lazily from the database.
+// A cursor holds a connection until it's closed, so it should always be closed:
+//
+//	cur, err := repo.OpenCursor(ctx, "status = ?", "active")
+//	if err != nil {
+//		...
+//	}
+//	defer cur.Close()
+//	for cur.Next() {
+//		item, err := cur.Scan()
+//		...
+//	}
+//	if err := cur.Err(); err != nil {
+//		...
+//	}
+type Cursor[T IBaseModel] struct {
+	ctx    context.Context
+	repo   *Repository[T]
+	db     *gorm.DB
+	rows   *sql.Rows
+	closed bool
+}
+
+// OpenCursor runs the query matching given conditions and returns a cursor over its records, ordered by
+// primary key unless conds order them. The records are read as the cursor advances. Unlike the other operations the cursor isn't
+// bound to the default timeout, it lives until it's closed or ctx is done.
+func (r *Repository[T]) OpenCursor(ctx context.Context, conds ...interface{}) (*Cursor[T], error) {
+	var cursor *Cursor[T]
+	err := r.run(ctx, "OpenCursor", func(ctx context.Context) (int64, error) {
+		db := r.query(ctx, conds).Scopes(orderedByPrimaryKey)
+		rows, err := db.Rows()
+
+		if err != nil {
+			return 0, err
+		}
+
+		cursor = &Cursor[T]{ctx: ctx, repo: r, db: db, rows: rows}
+
+		return 0, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// Next advances the cursor to the next record, it returns false when there are no more records
+// or an error occurred, which is reported by Err
+func (c *Cursor[T]) Next() bool {
+	if c.closed {
+		return false
+	}
+
+	return c.rows.Next()
+}
+
+// Scan returns the record the cursor is at, Next should be called before each Scan
+func (c *Cursor[T]) Scan() (T, error) {
+	var item T
+
+	if err := c.db.ScanRows(c.rows, &item); err != nil {
+		return item, err
+	}
+
+	return item, c.repo.decrypt(c.ctx, &item)
+}
+
+// Err returns the error which ended the iteration, if any
+func (c *Cursor[T]) Err() error {
+	return c.rows.Err()
+}
+
+// Close releases the connection held by the cursor, closing a closed cursor does nothing
+func (c *Cursor[T]) Close() error {
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+
+	return c.rows.Close()
+}
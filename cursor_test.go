@@ -0,0 +1,99 @@
+package regorm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenCursor(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol")
+
+	cur, err := repo.OpenCursor(ctx, "age >= ?", 20)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+
+	for cur.Next() {
+		user, err := cur.Scan()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, user.Name)
+	}
+
+	if err := cur.Err(); err != nil || len(names) != 3 || names[0] != "alice" || names[2] != "carol" {
+		t.Fatalf("got %v, %v, want every user", names, err)
+	}
+
+	if err := cur.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cur.Close(); err != nil {
+		t.Fatalf("got %v, want closing again to do nothing", err)
+	}
+
+	if cur.Next() {
+		t.Fatal("got a record, want none from a closed cursor")
+	}
+
+	if cur, err = repo.OpenCursor(ctx, OrderBy("name", true)); err != nil {
+		t.Fatal(err)
+	}
+
+	defer cur.Close()
+
+	names = nil
+
+	for cur.Next() {
+		user, err := cur.Scan()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, user.Name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"carol", "bob", "alice"}) {
+		t.Fatalf("got %v, want the order of the conditions", names)
+	}
+}
+
+func TestOpenCursorTimeout(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	seed(t, repo, "alice", "bob")
+	repo.SetDefaultTimeout(20 * time.Millisecond)
+
+	cur, err := repo.OpenCursor(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer cur.Close()
+
+	time.Sleep(30 * time.Millisecond)
+	n := 0
+
+	for cur.Next() {
+		if _, err := cur.Scan(); err != nil {
+			t.Fatal(err)
+		}
+
+		n++
+	}
+
+	if err := cur.Err(); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want the cursor outliving the timeout", n, err)
+	}
+}
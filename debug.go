@@ -0,0 +1,148 @@
+package regorm
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// DefaultNPlusOneThreshold is the number of times the same query can run during a logical operation
+// before debug mode reports it as an N+1 query
+const DefaultNPlusOneThreshold = 5
+
+// nPlusOneCallback is the name of the GORM callbacks detecting N+1 queries
+const nPlusOneCallback = "regorm:detect_n_plus_one"
+
+// EnableNPlusOneDetection registers the GORM callbacks detecting N+1 queries on db, it's meant to be called
+// once while setting the database up, before the repositories run queries. The callbacks are global to db and
+// the sessions derived from it, but they only count the statements of the repositories in debug mode:
+//
+//	if err := regorm.EnableNPlusOneDetection(db); err != nil {
+//		return err
+//	}
+//	repo := regorm.InitRepository[User](db)
+//	repo.SetDebug(true)
+//
+// Registering the callbacks again on the same database does nothing.
+func EnableNPlusOneDetection(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if callbacks.Query().Get(nPlusOneCallback) == nil {
+		if err := callbacks.Query().After("gorm:query").Register(nPlusOneCallback, detectNPlusOne); err != nil {
+			return err
+		}
+	}
+
+	if callbacks.Row().Get(nPlusOneCallback) == nil {
+		if err := callbacks.Row().After("gorm:row").Register(nPlusOneCallback, detectNPlusOne); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetDebug enables or disables the detection of N+1 queries for the repository, the detection callbacks must
+// be registered on the database with EnableNPlusOneDetection. In debug mode the statements repository runs
+// within a logical operation started with TrackQueries are counted by their SQL template, a query running
+// more than the N+1 threshold times is reported once to the callback set with OnNPlusOne, or else logged as
+// a warning by the logger of the database:
+//
+//	repo.SetDebug(true)
+//	ctx = regorm.TrackQueries(ctx)
+//	repo.Find(ctx, &users)
+//	for _, user := range users {
+//		orderRepo.First(ctx, &order, "user_id = ?", user.ID) // reported once it runs more than the threshold
+//	}
+//
+// Statements running with contexts which aren't tracked are never reported.
+func (r *Repository[T]) SetDebug(enabled bool) {
+	r.debug = enabled
+}
+
+// SetNPlusOneThreshold sets the number of times the same query can run during a logical operation
+// before it's reported as an N+1 query, threshold <= 0 falls back to DefaultNPlusOneThreshold
+func (r *Repository[T]) SetNPlusOneThreshold(threshold int) {
+	r.nPlusOneThreshold = threshold
+}
+
+// OnNPlusOne sets fn as the callback called in debug mode with the SQL template of the queries running
+// more than the N+1 threshold times during a logical operation, and the number of times they ran
+func (r *Repository[T]) OnNPlusOne(fn func(query string, count int)) {
+	r.onNPlusOne = fn
+}
+
+// TrackQueries returns a copy of ctx starting a logical operation, like the handling of a request,
+// during which the repositories in debug mode count their queries to detect N+1 queries
+func TrackQueries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryTrackerKey{}, &queryTracker{counts: map[string]int{}})
+}
+
+// queryTrackerKey is the context key of the queryTracker of a logical operation
+type queryTrackerKey struct{}
+
+// queryTracker counts the queries which ran during a logical operation by their SQL template
+type queryTracker struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// debugKey is the context key of the debugConfig of the repository running a statement
+type debugKey struct{}
+
+// debugConfig is the N+1 detection configuration of the repository running a statement
+type debugConfig struct {
+	threshold  int
+	onNPlusOne func(query string, count int)
+}
+
+// debugged returns ctx carrying the N+1 detection configuration of repository when it's in debug mode
+func (r *Repository[T]) debugged(ctx context.Context) context.Context {
+	if !r.debug {
+		return ctx
+	}
+
+	threshold := r.nPlusOneThreshold
+
+	if threshold <= 0 {
+		threshold = DefaultNPlusOneThreshold
+	}
+
+	return context.WithValue(ctx, debugKey{}, &debugConfig{threshold: threshold, onNPlusOne: r.onNPlusOne})
+}
+
+// detectNPlusOne is the GORM callback counting the statements of a repository in debug mode
+// and reporting the queries which run more than its N+1 threshold times
+func detectNPlusOne(db *gorm.DB) {
+	ctx := db.Statement.Context
+	config, ok := ctx.Value(debugKey{}).(*debugConfig)
+
+	if !ok {
+		return
+	}
+
+	tracker, ok := ctx.Value(queryTrackerKey{}).(*queryTracker)
+
+	if !ok {
+		return
+	}
+
+	query := db.Statement.SQL.String()
+
+	tracker.mu.Lock()
+	tracker.counts[query]++
+	count := tracker.counts[query]
+	tracker.mu.Unlock()
+
+	if count != config.threshold+1 {
+		return
+	}
+
+	if config.onNPlusOne != nil {
+		config.onNPlusOne(query, count)
+		return
+	}
+
+	db.Logger.Warn(ctx, "regorm: possible N+1 query, %q ran %d times", query, count)
+}
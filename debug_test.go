@@ -0,0 +1,133 @@
+package regorm
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm/logger"
+)
+
+// nPlusOne records the N+1 queries reported to OnNPlusOne
+type nPlusOne struct {
+	queries []string
+	counts  []int
+}
+
+func (n *nPlusOne) report(query string, count int) {
+	n.queries = append(n.queries, query)
+	n.counts = append(n.counts, count)
+}
+
+func TestNPlusOne(t *testing.T) {
+	db := openDB(t, &Customer{}, &Purchase{}, &Item{})
+
+	if err := EnableNPlusOneDetection(db); err != nil {
+		t.Fatal(err)
+	}
+
+	customers := InitRepository[Customer](db)
+	orders := InitRepository[Purchase](db)
+	seedCustomers(t, customers)
+	seedCustomers(t, customers)
+
+	reported := &nPlusOne{}
+
+	customers.SetDebug(true)
+	orders.SetDebug(true)
+	orders.SetNPlusOneThreshold(2)
+	orders.OnNPlusOne(reported.report)
+
+	ctx := TrackQueries(context.Background())
+
+	var found []Customer
+
+	if err := customers.Find(ctx, &found); err != nil || len(found) != 4 {
+		t.Fatalf("got %+v, %v, want 4 customers", found, err)
+	}
+
+	for _, customer := range found {
+		var order Purchase
+
+		if err := orders.First(ctx, &order, "customer_id = ?", customer.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(reported.queries) != 1 || reported.counts[0] != 3 || !strings.Contains(reported.queries[0], "customer_id = ?") {
+		t.Fatalf("got %v %v, want the per customer query reported once", reported.queries, reported.counts)
+	}
+
+	reported.queries, reported.counts = nil, nil
+	ctx = TrackQueries(context.Background())
+
+	if err := customers.Find(ctx, &found, Preload("Orders.Items")); err != nil || len(found[0].Orders) != 2 {
+		t.Fatalf("got %+v, %v, want the orders preloaded", found, err)
+	}
+
+	if len(reported.queries) != 0 {
+		t.Fatalf("got %v, want no N+1 query for a preloaded Find", reported.queries)
+	}
+
+	for _, customer := range found {
+		var order Purchase
+
+		if err := orders.First(context.Background(), &order, "customer_id = ?", customer.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(reported.queries) != 0 {
+		t.Fatalf("got %v, want the queries of untracked contexts ignored", reported.queries)
+	}
+}
+
+func TestNPlusOneWarning(t *testing.T) {
+	db := openDB(t)
+	var buf bytes.Buffer
+	db.Logger = logger.New(log.New(&buf, "", 0), logger.Config{LogLevel: logger.Warn})
+
+	if err := EnableNPlusOneDetection(db); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := InitRepository[User](db)
+	users := seed(t, repo, "alice", "bob", "carol")
+	repo.SetDebug(true)
+	repo.SetNPlusOneThreshold(2)
+	ctx := TrackQueries(context.Background())
+
+	for _, user := range users {
+		var found User
+
+		if err := repo.First(ctx, &found, user.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !strings.Contains(buf.String(), "possible N+1 query") {
+		t.Fatalf("got %q, want a N+1 warning logged", buf.String())
+	}
+}
+
+func TestEnableNPlusOneDetection(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	repo.SetDebug(true)
+
+	if db.Callback().Query().Get(nPlusOneCallback) != nil {
+		t.Fatal("got the N+1 callbacks registered by SetDebug, want them registered by EnableNPlusOneDetection only")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := EnableNPlusOneDetection(db); err != nil {
+			t.Fatalf("got %v, want the callbacks registered once", err)
+		}
+	}
+
+	if db.Callback().Query().Get(nPlusOneCallback) == nil || db.Callback().Row().Get(nPlusOneCallback) == nil {
+		t.Fatal("got no N+1 callbacks, want the query and row callbacks registered")
+	}
+}
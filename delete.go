@@ -0,0 +1,114 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+)
+
+// DeleteByID deletes the record whose primary key equals id without loading it first and
+// returns rows affected, soft deletes if the model includes a deleted_at field
+func (r *Repository[T]) DeleteByID(ctx context.Context, id interface{}) (int64, error) {
+	return r.runRows(ctx, "DeleteByID", func(ctx context.Context) (int64, error) {
+		cond, err := r.byID(id)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.remove(r.db(ctx).Where(cond), new(T))
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// ForceDelete permanently deletes value matching given conditions, unlike Delete
+// it doesn't soft delete even if value includes a deleted_at field
+func (r *Repository[T]) ForceDelete(ctx context.Context, model *T) (int64, error) {
+	return r.runRows(ctx, "ForceDelete", func(ctx context.Context) (int64, error) {
+		db, err := r.keyed(ctx, r.db(ctx).Unscoped(), model)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := db.Delete(model)
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// Restore restores soft deleted value by setting its deleted_at to null and returns rows affected,
+// returns ErrNotSoftDeletable if the model has no gorm.DeletedAt field nor soft delete column.
+// A soft delete flag set with SetSoftDeleteColumn is set to false instead.
+func (r *Repository[T]) Restore(ctx context.Context, model *T) (int64, error) {
+	return r.runRows(ctx, "Restore", func(ctx context.Context) (int64, error) {
+		field, err := r.softDeleteField()
+
+		if err != nil {
+			return 0, err
+		}
+
+		db, err := r.keyed(ctx, r.db(ctx).Unscoped(), model)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := db.Model(model).Update(field.DBName, restoredValue(field))
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// DeleteWhere deletes all the records matching conds in a single statement and returns rows affected,
+// soft deletes if the model includes a deleted_at field. Returns ErrMissingCondition if conds is empty
+// to avoid deleting the whole table.
+func (r *Repository[T]) DeleteWhere(ctx context.Context, conds interface{}) (int64, error) {
+	return r.runRows(ctx, "DeleteWhere", func(ctx context.Context) (int64, error) {
+		if emptyCondition(conds) {
+			return 0, ErrMissingCondition
+		}
+
+		res := r.remove(r.db(ctx).Where(conds), new(T))
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// DeleteInChunks deletes the records matching conds chunkSize records at a time until none remain and returns
+// the total rows affected, each chunk is deleted by its own statement so locks are released between chunks.
+// Records are soft deleted like DeleteWhere. Returns ErrMissingCondition if conds is empty to avoid deleting
+// the whole table, chunkSize <= 0 falls back to DefaultPageSize.
+func (r *Repository[T]) DeleteInChunks(ctx context.Context, conds interface{}, chunkSize int) (int64, error) {
+	_, chunkSize = normalizePage(DefaultPage, chunkSize)
+
+	return r.runRows(ctx, "DeleteInChunks", func(ctx context.Context) (int64, error) {
+		if emptyCondition(conds) {
+			return 0, ErrMissingCondition
+		}
+
+		column, err := r.primaryKey()
+
+		if err != nil {
+			return 0, err
+		}
+
+		var total int64
+
+		for {
+			var ids []interface{}
+
+			if err := r.db(ctx).Model(new(T)).Where(conds).Limit(chunkSize).Pluck(column, &ids).Error; err != nil || len(ids) == 0 {
+				return total, err
+			}
+
+			res := r.remove(r.db(ctx).Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Values: ids}), new(T))
+			total += res.RowsAffected
+
+			if res.Error != nil || len(ids) < chunkSize {
+				return total, res.Error
+			}
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestDeleteWhere(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "draft", "published", "draft", "draft")
+
+	n, err := repo.DeleteWhere(ctx, map[string]interface{}{"name": "draft"})
+
+	if err != nil || n != 3 {
+		t.Fatalf("got %d, %v, want 3 rows affected", n, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 record left", n, err)
+	}
+
+	var total int64
+
+	if err := repo.GetDB().Unscoped().Model(&User{}).Count(&total).Error; err != nil || total != 4 {
+		t.Fatalf("got %d, %v, want the records soft deleted", total, err)
+	}
+
+	for _, conds := range []interface{}{nil, "", map[string]interface{}{}, User{}} {
+		if _, err := repo.DeleteWhere(ctx, conds); !errors.Is(err, ErrMissingCondition) {
+			t.Fatalf("%#v: got %v, want ErrMissingCondition", conds, err)
+		}
+	}
+}
+
+func TestForceDelete(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice", "bob")
+
+	n, err := repo.ForceDelete(ctx, users[0])
+
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	var total int64
+
+	if err := repo.GetDB().Unscoped().Model(&User{}).Where("id = ?", users[0].ID).Count(&total).Error; err != nil || total != 0 {
+		t.Fatalf("got %d, %v, want the record gone from unscoped queries", total, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want bob left", n, err)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice")
+
+	if _, err := repo.Delete(ctx, users[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	var found []User
+
+	if err := repo.Find(ctx, &found); err != nil || len(found) != 0 {
+		t.Fatalf("got %+v, %v, want the record soft deleted", found, err)
+	}
+
+	n, err := repo.Restore(ctx, users[0])
+
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	if err := repo.Find(ctx, &found); err != nil || len(found) != 1 || found[0].Name != "alice" {
+		t.Fatalf("got %+v, %v, want the record restored", found, err)
+	}
+
+	if _, err := InitRepository[Product](openDB(t, &Product{})).Restore(ctx, &Product{ID: 1}); !errors.Is(err, ErrNotSoftDeletable) {
+		t.Fatalf("got %v, want ErrNotSoftDeletable", err)
+	}
+}
+
+// Membership is a soft deletable model with a composite primary key
+type Membership struct {
+	UserID    uint `gorm:"primaryKey;autoIncrement:false"`
+	GroupID   uint `gorm:"primaryKey;autoIncrement:false"`
+	DeletedAt gorm.DeletedAt
+}
+
+func (Membership) TableName() string { return "memberships" }
+
+func TestRestoreCompositeKey(t *testing.T) {
+	repo := InitRepository[Membership](openDB(t, &Membership{}))
+	ctx := context.Background()
+	memberships := []*Membership{{UserID: 1, GroupID: 0}, {UserID: 1, GroupID: 1}, {UserID: 2, GroupID: 0}}
+
+	if _, err := repo.BatchCreate(ctx, memberships); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, membership := range memberships {
+		if _, err := repo.Delete(ctx, membership); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// GORM alone would match every membership of user 1, the zero group key left out
+	if n, err := repo.Restore(ctx, memberships[0]); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want only (1, 0) restored", n, err)
+	}
+
+	var found []Membership
+
+	if err := repo.Find(ctx, &found); err != nil || len(found) != 1 || found[0].UserID != 1 || found[0].GroupID != 0 {
+		t.Fatalf("got %+v, %v, want (1, 0) restored", found, err)
+	}
+}
+
+func TestDeleteByID(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice")
+
+	if n, err := repo.DeleteByID(ctx, 1); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	if n, err := repo.DeleteByID(ctx, 42); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want 0 rows affected without error", n, err)
+	}
+
+	var total int64
+
+	if err := repo.GetDB().Unscoped().Model(&User{}).Count(&total).Error; err != nil || total != 1 {
+		t.Fatalf("got %d, %v, want the record soft deleted", total, err)
+	}
+}
+
+func TestDeleteInChunks(t *testing.T) {
+	db := openDB(t, &Product{})
+	repo := InitRepository[Product](db)
+	ctx := context.Background()
+	products := make([]*Product, 0, 30)
+
+	for i := 0; i < 30; i++ {
+		products = append(products, &Product{Code: fmt.Sprintf("p%02d", i), Price: i % 6})
+	}
+
+	if _, err := repo.BatchCreate(ctx, products); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := record(t, db)
+	rows, err := repo.DeleteInChunks(ctx, "price > 0", 10)
+
+	if err != nil || rows != 25 {
+		t.Fatalf("got %d, %v, want 25 deleted", rows, err)
+	}
+
+	if n := rec.executed("DELETE"); n != 3 {
+		t.Fatalf("got %d statements, want 3 chunks", n)
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 5 {
+		t.Fatalf("got %d, %v, want the 5 free products left", count, err)
+	}
+
+	if _, err := repo.DeleteInChunks(ctx, nil, 10); !errors.Is(err, ErrMissingCondition) {
+		t.Fatalf("got %v, want ErrMissingCondition", err)
+	}
+}
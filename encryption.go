@@ -0,0 +1,235 @@
+package regorm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// FieldEncryptor encrypts the values of the encrypted fields of a repository, see SetEncryption
+type FieldEncryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// SetEncryption makes repository store fields encrypted with encryptor. The writes of models like Create, CreateOmit,
+// CreateReturning, BatchCreate, Upsert, BatchUpsert, Update and UpdateOmit encrypt the fields before writing, the models
+// keep their plaintext values. UpdateColumns and UpdateWhere encrypt the values of the encrypted columns, which must be
+// strings or []byte. Every read scanning into models like First, Find, Paginate, the trashed reads, FindInBatches,
+// Stream or FirstOrCreate decrypts them after reading.
+//
+// Fields are the names or columns of string or []byte fields, encrypted string fields are stored base64 encoded.
+// Empty values are stored as is. As the database only sees the ciphertext, conditions on encrypted fields don't match.
+func (r *Repository[T]) SetEncryption(encryptor FieldEncryptor, fields ...string) {
+	r.encryptor = encryptor
+	r.encryptedFields = fields
+}
+
+// encrypted wraps fn writing models to write their encrypted fields encrypted, the plaintext values are restored after
+func (r *Repository[T]) encrypted(ctx context.Context, models []*T, fn func() (int64, error)) func() (int64, error) {
+	if r.encryptor == nil || len(r.encryptedFields) == 0 {
+		return fn
+	}
+
+	return func() (int64, error) {
+		values, err := r.encryptedValues(ctx, models)
+
+		if err != nil {
+			return 0, err
+		}
+
+		plaintexts := make([]reflect.Value, 0, len(values))
+
+		defer func() {
+			for i, plaintext := range plaintexts {
+				values[i].Set(plaintext)
+			}
+		}()
+
+		for _, value := range values {
+			plaintexts = append(plaintexts, reflect.ValueOf(value.Interface()))
+
+			if err := r.crypt(value, true); err != nil {
+				return 0, err
+			}
+		}
+
+		return fn()
+	}
+}
+
+// encryptedColumns returns a copy of the column values with the values of the encrypted fields encrypted,
+// returns ErrInvalidColumn if such a value isn't a string or []byte
+func (r *Repository[T]) encryptedColumns(values map[string]interface{}) (map[string]interface{}, error) {
+	if r.encryptor == nil || len(r.encryptedFields) == 0 {
+		return values, nil
+	}
+
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := make(map[*schema.Field]bool, len(r.encryptedFields))
+
+	for _, name := range r.encryptedFields {
+		field, err := parseField(s, name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		encrypted[field] = true
+	}
+
+	columns := make(map[string]interface{}, len(values))
+
+	for column, value := range values {
+		columns[column] = value
+
+		if field := s.LookUpField(column); field == nil || !encrypted[field] || value == nil {
+			continue
+		}
+
+		switch value.(type) {
+		case string, []byte:
+		default:
+			return nil, fmt.Errorf("%w: %q is encrypted, it can only be set to a string or []byte", ErrInvalidColumn, column)
+		}
+
+		ciphertext := reflect.New(reflect.TypeOf(value)).Elem()
+		ciphertext.Set(reflect.ValueOf(value))
+
+		if err := r.crypt(ciphertext, true); err != nil {
+			return nil, err
+		}
+
+		columns[column] = ciphertext.Interface()
+	}
+
+	return columns, nil
+}
+
+// decrypt decrypts the encrypted fields of dest, a *T or a *[]T which has been read
+func (r *Repository[T]) decrypt(ctx context.Context, dest interface{}) error {
+	if r.encryptor == nil || len(r.encryptedFields) == 0 {
+		return nil
+	}
+
+	var models []*T
+
+	switch dest := dest.(type) {
+	case *T:
+		models = []*T{dest}
+	case *[]T:
+		for i := range *dest {
+			models = append(models, &(*dest)[i])
+		}
+	}
+
+	values, err := r.encryptedValues(ctx, models)
+
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		if err := r.crypt(value, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encryptedValues returns the values of the encrypted fields of models
+func (r *Repository[T]) encryptedValues(ctx context.Context, models []*T) ([]reflect.Value, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]reflect.Value, 0, len(models)*len(r.encryptedFields))
+
+	for _, name := range r.encryptedFields {
+		field, err := parseField(s, name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if kind := field.FieldType.Kind(); kind != reflect.String && (kind != reflect.Slice || field.FieldType.Elem().Kind() != reflect.Uint8) {
+			return nil, fmt.Errorf("%w: %q isn't a string or []byte field", ErrInvalidColumn, name)
+		}
+
+		for _, model := range models {
+			if model != nil {
+				values = append(values, field.ReflectValueOf(ctx, reflect.ValueOf(model).Elem()))
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// crypt replaces the string or []byte value with its encryption, or its decryption when encrypt
+// is false, empty values are left as is
+func (r *Repository[T]) crypt(value reflect.Value, encrypt bool) error {
+	if value.Len() == 0 {
+		return nil
+	}
+
+	if value.Kind() != reflect.String {
+		data, err := r.cipher(value.Bytes(), encrypt)
+
+		if err != nil {
+			return err
+		}
+
+		value.SetBytes(data)
+
+		return nil
+	}
+
+	if encrypt {
+		data, err := r.encryptor.Encrypt([]byte(value.String()))
+
+		if err != nil {
+			return err
+		}
+
+		value.SetString(base64.StdEncoding.EncodeToString(data))
+
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value.String())
+
+	if err != nil {
+		return err
+	}
+
+	data, err = r.encryptor.Decrypt(data)
+
+	if err != nil {
+		return err
+	}
+
+	value.SetString(string(data))
+
+	return nil
+}
+
+// cipher encrypts data, or decrypts it when encrypt is false
+func (r *Repository[T]) cipher(data []byte, encrypt bool) ([]byte, error) {
+	if encrypt {
+		return r.encryptor.Encrypt(data)
+	}
+
+	return r.encryptor.Decrypt(data)
+}
@@ -0,0 +1,198 @@
+package regorm
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// reverser is a FieldEncryptor reversing the bytes, so ciphertexts differ from plaintexts
+type reverser struct{}
+
+func (reverser) Encrypt(plaintext []byte) ([]byte, error) {
+	return reversed(plaintext), nil
+}
+
+func (reverser) Decrypt(ciphertext []byte) ([]byte, error) {
+	return reversed(ciphertext), nil
+}
+
+func reversed(data []byte) []byte {
+	data = slices.Clone(data)
+	slices.Reverse(data)
+
+	return data
+}
+
+// Secret has encrypted fields
+type Secret struct {
+	ID        uint
+	Name      string
+	Token     string
+	Key       []byte
+	DeletedAt gorm.DeletedAt
+}
+
+func (Secret) TableName() string { return "secrets" }
+
+// openSecrets returns a repository of secrets with encrypted tokens and keys, and its database
+func openSecrets(t *testing.T, names ...string) (IRepository[Secret], *gorm.DB) {
+	t.Helper()
+
+	db := openDB(t, &Secret{})
+	repo := InitRepository[Secret](db)
+	repo.SetEncryption(reverser{}, "Token", "key")
+
+	for _, name := range names {
+		if _, err := repo.Create(context.Background(), &Secret{Name: name, Token: name + "-token", Key: []byte(name)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repo, db
+}
+
+func TestSetEncryption(t *testing.T) {
+	repo, db := openSecrets(t)
+	ctx := context.Background()
+	secret := &Secret{Name: "alice", Token: "alice-token", Key: []byte("alice")}
+
+	if _, err := repo.Create(ctx, secret); err != nil || secret.Token != "alice-token" {
+		t.Fatalf("got %+v, %v, want the plaintext kept", secret, err)
+	}
+
+	var stored Secret
+
+	if err := db.First(&stored, secret.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if want := base64.StdEncoding.EncodeToString([]byte("nekot-ecila")); stored.Token != want || string(stored.Key) != "ecila" {
+		t.Fatalf("got %+v, want the token and the key stored encrypted", stored)
+	}
+
+	var found Secret
+
+	if err := repo.First(ctx, &found, secret.ID); err != nil || found.Token != "alice-token" || string(found.Key) != "alice" {
+		t.Fatalf("got %+v, %v, want decrypted", found, err)
+	}
+}
+
+func TestEncryptionReads(t *testing.T) {
+	repo, _ := openSecrets(t, "alice", "bob")
+	ctx := context.Background()
+
+	if _, err := repo.Delete(ctx, &Secret{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	reads := map[string]func() ([]Secret, error){
+		"Paginate": func() ([]Secret, error) {
+			var secrets []Secret
+			return secrets, repo.Paginate(ctx, &secrets, 1, 10)
+		},
+		"FindPaginated": func() ([]Secret, error) {
+			page, err := repo.FindPaginated(ctx, 1, 10)
+			if err != nil {
+				return nil, err
+			}
+			return page.Items, nil
+		},
+		"FindAndCount": func() ([]Secret, error) {
+			var secrets []Secret
+			_, err := repo.FindAndCount(ctx, &secrets, 1, 10)
+			return secrets, err
+		},
+		"FindAfter": func() ([]Secret, error) {
+			var secrets []Secret
+			return secrets, repo.FindAfter(ctx, &secrets, "id", 0, 10)
+		},
+		"Search": func() ([]Secret, error) {
+			var secrets []Secret
+			return secrets, repo.Search(ctx, &secrets, []string{"name"}, "alice")
+		},
+		"FindWithTrashed": func() ([]Secret, error) {
+			var secrets []Secret
+			return secrets, repo.FindWithTrashed(ctx, &secrets, "id = ?", 1)
+		},
+		"FirstWithTrashed": func() ([]Secret, error) {
+			var secret Secret
+			err := repo.FirstWithTrashed(ctx, &secret, 2)
+			return []Secret{secret}, err
+		},
+		"FindOnlyTrashed": func() ([]Secret, error) {
+			var secrets []Secret
+			return secrets, repo.FindOnlyTrashed(ctx, &secrets)
+		},
+		"FirstOnlyTrashed": func() ([]Secret, error) {
+			var secret Secret
+			err := repo.FirstOnlyTrashed(ctx, &secret)
+			return []Secret{secret}, err
+		},
+		"FindInBatches": func() ([]Secret, error) {
+			var secrets []Secret
+			return secrets, repo.FindInBatches(ctx, 1, func(batch []Secret) error {
+				secrets = append(secrets, batch...)
+				return nil
+			})
+		},
+		"Stream": func() ([]Secret, error) {
+			var secrets []Secret
+			items, errs := repo.Stream(ctx)
+			for item := range items {
+				secrets = append(secrets, item)
+			}
+			return secrets, <-errs
+		},
+	}
+
+	for name, read := range reads {
+		secrets, err := read()
+
+		if err != nil || len(secrets) != 1 {
+			t.Errorf("%s: got %+v, %v, want a single secret", name, secrets, err)
+			continue
+		}
+
+		if secret := secrets[0]; secret.Token != secret.Name+"-token" || string(secret.Key) != secret.Name {
+			t.Errorf("%s: got %+v, want decrypted", name, secret)
+		}
+	}
+}
+
+func TestEncryptionUpdateColumns(t *testing.T) {
+	repo, db := openSecrets(t, "alice", "bob")
+	ctx := context.Background()
+
+	if _, err := repo.UpdateColumns(ctx, map[string]interface{}{"id": 1}, map[string]interface{}{"token": "new", "Key": []byte("k1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.UpdateWhere(ctx, map[string]interface{}{"name": "bob"}, map[string]interface{}{"token": "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored []Secret
+
+	if err := db.Order("id").Find(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if stored[0].Token != base64.StdEncoding.EncodeToString([]byte("wen")) || string(stored[0].Key) != "1k" || stored[1].Token == "other" {
+		t.Fatalf("got %+v, want the values stored encrypted", stored)
+	}
+
+	var secrets []Secret
+
+	if err := repo.Find(ctx, &secrets); err != nil || secrets[0].Token != "new" || string(secrets[0].Key) != "k1" || secrets[1].Token != "other" {
+		t.Fatalf("got %+v, %v, want the updated values decrypted", secrets, err)
+	}
+
+	if _, err := repo.UpdateColumns(ctx, map[string]interface{}{"id": 1}, map[string]interface{}{"token": gorm.Expr("name")}); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
@@ -0,0 +1,63 @@
+package regorm
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is returned by OrFail methods when no record matches, it wraps
+	// gorm.ErrRecordNotFound so both errors.Is(err, ErrNotFound) and
+	// errors.Is(err, gorm.ErrRecordNotFound) report true
+	ErrNotFound = errors.New("regorm: not found")
+
+	// ErrInvalidColumn is returned when a column name doesn't exist in the model schema
+	ErrInvalidColumn = errors.New("regorm: invalid column")
+
+	// ErrNotInTransaction is returned when Commit or Rollback is called on a repository
+	// which isn't bound to a transaction
+	ErrNotInTransaction = errors.New("regorm: repository is not in a transaction")
+
+	// ErrMissingCondition is returned by bulk writes when the condition is empty,
+	// to avoid writing to the whole table by accident
+	ErrMissingCondition = errors.New("regorm: missing condition")
+
+	// ErrNotSoftDeletable is returned by soft delete specific methods when the model has no soft delete column
+	ErrNotSoftDeletable = errors.New("regorm: model is not soft deletable")
+
+	// ErrInvalidPrimaryKey is returned by ID based methods when the model has no single primary key
+	ErrInvalidPrimaryKey = errors.New("regorm: model has no single primary key")
+
+	// ErrInvalidCompositeKey is returned by FindByCompositeKey when the keys aren't exactly the model primary keys
+	ErrInvalidCompositeKey = errors.New("regorm: keys don't match the model primary keys")
+
+	// ErrInvalidAssociation is returned by association methods when the model has no such association
+	ErrInvalidAssociation = errors.New("regorm: invalid association")
+
+	// ErrInvalidJSONPath is returned by WhereJSON when a key of the path isn't made of letters, digits and underscores
+	ErrInvalidJSONPath = errors.New("regorm: invalid JSON path")
+
+	// ErrInvalidSchema is returned by the operations of a repository WithSchema when the schema name isn't made of letters, digits and underscores
+	ErrInvalidSchema = errors.New("regorm: invalid schema name")
+
+	// ErrUnsupportedDialect is returned by the methods building dialect specific SQL for other dialects
+	ErrUnsupportedDialect = errors.New("regorm: unsupported database dialect")
+
+	// ErrNotSupported is returned by MockRepository for the methods and conditions it can't emulate in memory
+	ErrNotSupported = errors.New("regorm: not supported by the mock repository")
+
+	// ErrOptimisticLock is returned by Update when the version of the record
+	// doesn't match the version of the model, so it's changed since it's loaded
+	ErrOptimisticLock = errors.New("regorm: record is changed by another update")
+)
+
+// notFound wraps gorm.ErrRecordNotFound with ErrNotFound, other errors are returned as is
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	return err
+}
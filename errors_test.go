@@ -0,0 +1,35 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestErrNotFound(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+
+	var user User
+	var users []User
+
+	for name, err := range map[string]error{
+		"FirstOrFail":    repo.FirstOrFail(ctx, &user),
+		"LastOrFail":     repo.LastOrFail(ctx, &user),
+		"TakeOrFail":     repo.TakeOrFail(ctx, &user),
+		"FindByIDOrFail": repo.FindByIDOrFail(ctx, &user, 1),
+		"FindOrFail":     repo.FindOrFail(ctx, &users),
+	} {
+		if !errors.Is(err, ErrNotFound) || !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("%s: got %v, want both ErrNotFound and gorm.ErrRecordNotFound", name, err)
+		}
+	}
+
+	other := errors.New("other")
+
+	if err := notFound(other); err != other {
+		t.Fatalf("got %v, want other errors returned as is", err)
+	}
+}
@@ -0,0 +1,38 @@
+package regorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Ping verifies the database of the repository is reachable, for liveness and readiness probes
+func (r *Repository[T]) Ping(ctx context.Context) error {
+	return r.run(ctx, "Ping", func(ctx context.Context) (int64, error) {
+		sqlDB, err := r.Database.DB()
+
+		if err != nil {
+			return 0, fmt.Errorf("regorm: can't get the database handle: %w", err)
+		}
+
+		return 0, sqlDB.PingContext(ctx)
+	})
+}
+
+// ConfigurePool sets the connection pool limits of the database of the repository, a connMaxLifetime
+// of zero keeps connections forever. The pool is shared by all the repositories of the database.
+func (r *Repository[T]) ConfigurePool(maxOpen, maxIdle int, connMaxLifetime time.Duration) error {
+	return r.run(context.Background(), "ConfigurePool", func(ctx context.Context) (int64, error) {
+		sqlDB, err := r.Database.DB()
+
+		if err != nil {
+			return 0, fmt.Errorf("regorm: can't get the database handle: %w", err)
+		}
+
+		sqlDB.SetMaxOpenConns(maxOpen)
+		sqlDB.SetMaxIdleConns(maxIdle)
+		sqlDB.SetConnMaxLifetime(connMaxLifetime)
+
+		return 0, nil
+	})
+}
@@ -0,0 +1,55 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+
+	if err := repo.Ping(context.Background()); err != nil {
+		t.Fatalf("got %v, want a reachable database", err)
+	}
+
+	sqlDB, err := db.DB()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = sqlDB.Close()
+
+	if err := repo.Ping(context.Background()); err == nil {
+		t.Fatal("got no error, want a closed database")
+	}
+}
+
+func TestConfigurePool(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+
+	if err := repo.ConfigurePool(7, 2, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := sqlDB.Stats(); stats.MaxOpenConnections != 7 {
+		t.Fatalf("got %d max open connections, want 7", stats.MaxOpenConnections)
+	}
+
+	if _, err := repo.Count(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := sqlDB.Stats(); stats.Idle > 2 {
+		t.Fatalf("got %d idle connections, want at most 2", stats.Idle)
+	}
+}
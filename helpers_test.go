@@ -0,0 +1,236 @@
+package regorm
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// User is the model most tests run against
+type User struct {
+	ID        uint
+	Name      string
+	Email     string
+	Age       int
+	DeletedAt gorm.DeletedAt
+}
+
+func (User) TableName() string { return "users" }
+
+// Customer has many purchases as Orders, which have many items
+type Customer struct {
+	ID     uint
+	Name   string
+	Orders []Purchase
+}
+
+func (Customer) TableName() string { return "customers" }
+
+type Purchase struct {
+	ID         uint
+	CustomerID uint
+	Status     string
+	Items      []Item
+}
+
+func (Purchase) TableName() string { return "purchases" }
+
+type Item struct {
+	ID         uint
+	PurchaseID uint
+	Name       string
+}
+
+func (Item) TableName() string { return "items" }
+
+// openDB opens a SQLite database in a temporary file with the models migrated
+func openDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	return openDSN(t, "", models...)
+}
+
+// openDSN opens a SQLite database like openDB with the extra DSN params
+func openDSN(t *testing.T, params string, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_journal_mode=WAL" + params
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(append([]interface{}{&User{}}, models...)...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+// dialector is a SQLite dialector reporting another name, it lets tests check the SQL built for other dialects
+type dialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d dialector) Name() string { return d.name }
+
+func (d dialector) Initialize(db *gorm.DB) error {
+	// the SQLite builders drop the locking clauses and the schema of tables
+	db.ClauseBuilders = map[string]clause.ClauseBuilder{
+		"FOR":    func(c clause.Clause, builder clause.Builder) { c.Build(builder) },
+		"INSERT": func(c clause.Clause, builder clause.Builder) { c.Build(builder) },
+	}
+
+	return d.Dialector.Initialize(db)
+}
+
+// openDialect opens a SQLite database like openDB whose dialector reports name, in dry run mode
+// as the SQL built for other dialects can't run on SQLite
+func openDialect(t *testing.T, name string, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	db := openDB(t, models...)
+	named, err := gorm.Open(dialector{Dialector: db.Dialector, name: name}, &gorm.Config{
+		Logger:   logger.Discard,
+		ConnPool: db.ConnPool,
+		DryRun:   true,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return named
+}
+
+// recorder records the SQL of the statements run by a database, dry runs included
+type recorder struct {
+	mu   sync.Mutex
+	sqls []string
+	runs []string // the statements executed, without the dry runs
+}
+
+// record registers a recorder on db
+func record(t *testing.T, db *gorm.DB) *recorder {
+	t.Helper()
+
+	rec := &recorder{}
+	fn := func(tx *gorm.DB) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+
+		rec.sqls = append(rec.sqls, tx.Statement.SQL.String())
+
+		if !tx.DryRun {
+			rec.runs = append(rec.runs, tx.Statement.SQL.String())
+		}
+	}
+
+	callbacks := db.Callback()
+	errs := []error{
+		callbacks.Create().After("gorm:create").Register("test:record", fn),
+		callbacks.Query().After("gorm:query").Register("test:record", fn),
+		callbacks.Update().After("gorm:update").Register("test:record", fn),
+		callbacks.Delete().After("gorm:delete").Register("test:record", fn),
+		callbacks.Row().After("gorm:row").Register("test:record", fn),
+		callbacks.Raw().After("gorm:raw").Register("test:record", fn),
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rec
+}
+
+// all returns the recorded statements
+func (rec *recorder) all() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return append([]string{}, rec.sqls...)
+}
+
+// last returns the last recorded statement
+func (rec *recorder) last() string {
+	sqls := rec.all()
+
+	if len(sqls) == 0 {
+		return ""
+	}
+
+	return sqls[len(sqls)-1]
+}
+
+// count returns the number of recorded statements containing part
+func (rec *recorder) count(part string) int {
+	n := 0
+
+	for _, sql := range rec.all() {
+		if strings.Contains(sql, part) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// executed returns the number of executed statements containing part
+func (rec *recorder) executed(part string) int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	n := 0
+
+	for _, sql := range rec.runs {
+		if strings.Contains(sql, part) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// reset forgets the recorded statements
+func (rec *recorder) reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.sqls, rec.runs = nil, nil
+}
+
+// seed inserts users with the given names
+func seed(t *testing.T, repo IRepository[User], names ...string) []*User {
+	t.Helper()
+
+	users := make([]*User, 0, len(names))
+
+	for i, name := range names {
+		user := &User{Name: name, Email: name + "@example.com", Age: 20 + i}
+
+		if _, err := repo.Create(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users
+}
@@ -0,0 +1,118 @@
+package regorm
+
+import "context"
+
+// BeforeCreateHook is implemented by models to run logic before the repository inserts them
+// with Create, CreateOmit, CreateReturning, BatchCreate, BatchCreateInChunks or FirstOrCreate.
+//
+// Repository hooks wrap GORM's own hooks: BeforeRepositoryCreate runs before GORM's BeforeSave and BeforeCreate,
+// an error returned by it aborts the operation without reaching the database.
+//
+// Upsert, UpsertReturning, BatchUpsert and CreateIgnore run no repository hook, neither create nor update ones:
+// whether they insert a record, update it or skip it is only decided by the database.
+type BeforeCreateHook interface {
+	BeforeRepositoryCreate(ctx context.Context) error
+}
+
+// AfterCreateHook is implemented by models to run logic after the repository inserts them
+// with Create, CreateOmit, CreateReturning, BatchCreate, BatchCreateInChunks or FirstOrCreate.
+//
+// AfterRepositoryCreate runs after GORM's AfterCreate and AfterSave once the insert succeeded,
+// outside of GORM's hook transaction so an error returned by it doesn't roll the insert back.
+type AfterCreateHook interface {
+	AfterRepositoryCreate(ctx context.Context) error
+}
+
+// BeforeUpdateHook is implemented by models to run logic before the repository saves them
+// with Update, UpdateOmit or UpdateExisting.
+//
+// BeforeRepositoryUpdate runs before GORM's BeforeSave and BeforeUpdate,
+// an error returned by it aborts the operation without reaching the database.
+//
+// UpdateColumns, UpdateWhere, Increment and Decrement update records without a model and run no hook.
+type BeforeUpdateHook interface {
+	BeforeRepositoryUpdate(ctx context.Context) error
+}
+
+// AfterUpdateHook is implemented by models to run logic after the repository saves them
+// with Update, UpdateOmit or UpdateExisting.
+//
+// AfterRepositoryUpdate runs after GORM's AfterUpdate and AfterSave once the update succeeded,
+// outside of GORM's hook transaction so an error returned by it doesn't roll the update back.
+type AfterUpdateHook interface {
+	AfterRepositoryUpdate(ctx context.Context) error
+}
+
+// BeforeDeleteHook is implemented by models to run logic before the repository deletes them with Delete.
+//
+// BeforeRepositoryDelete runs before GORM's BeforeDelete,
+// an error returned by it aborts the operation without reaching the database.
+//
+// ForceDelete, DeleteByID, DeleteWhere and DeleteInChunks run no hook.
+type BeforeDeleteHook interface {
+	BeforeRepositoryDelete(ctx context.Context) error
+}
+
+// AfterDeleteHook is implemented by models to run logic after the repository deletes them with Delete.
+//
+// AfterRepositoryDelete runs after GORM's AfterDelete once the delete succeeded,
+// outside of GORM's hook transaction so an error returned by it doesn't roll the delete back.
+type AfterDeleteHook interface {
+	AfterRepositoryDelete(ctx context.Context) error
+}
+
+// createHooks runs fn inserting models between their BeforeCreateHook and AfterCreateHook
+func createHooks[T any](ctx context.Context, models []*T, fn func() (int64, error)) (int64, error) {
+	return withHooks(models, fn,
+		func(hook BeforeCreateHook) error { return hook.BeforeRepositoryCreate(ctx) },
+		func(hook AfterCreateHook) error { return hook.AfterRepositoryCreate(ctx) },
+	)
+}
+
+// updateHooks runs fn saving models between their BeforeUpdateHook and AfterUpdateHook
+func updateHooks[T any](ctx context.Context, models []*T, fn func() (int64, error)) (int64, error) {
+	return withHooks(models, fn,
+		func(hook BeforeUpdateHook) error { return hook.BeforeRepositoryUpdate(ctx) },
+		func(hook AfterUpdateHook) error { return hook.AfterRepositoryUpdate(ctx) },
+	)
+}
+
+// deleteHooks runs fn deleting models between their BeforeDeleteHook and AfterDeleteHook
+func deleteHooks[T any](ctx context.Context, models []*T, fn func() (int64, error)) (int64, error) {
+	return withHooks(models, fn,
+		func(hook BeforeDeleteHook) error { return hook.BeforeRepositoryDelete(ctx) },
+		func(hook AfterDeleteHook) error { return hook.AfterRepositoryDelete(ctx) },
+	)
+}
+
+// withHooks runs fn between the before and after hooks of models, after hooks run only when fn succeeded
+func withHooks[T any, B any, A any](models []*T, fn func() (int64, error), before func(B) error, after func(A) error) (int64, error) {
+	if err := callHooks(models, before); err != nil {
+		return 0, err
+	}
+
+	rows, err := fn()
+
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, callHooks(models, after)
+}
+
+// callHooks calls call with each of models implementing the hook H
+func callHooks[T any, H any](models []*T, call func(H) error) error {
+	for _, model := range models {
+		hook, ok := any(model).(H)
+
+		if !ok {
+			continue
+		}
+
+		if err := call(hook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Hooked records the repository hooks called on it
+type Hooked struct {
+	ID    uint
+	Code  string `gorm:"uniqueIndex"`
+	calls []string
+	fail  error
+}
+
+func (Hooked) TableName() string { return "hooked" }
+
+func (h *Hooked) BeforeRepositoryCreate(ctx context.Context) error {
+	h.calls = append(h.calls, "BeforeCreate")
+	return h.fail
+}
+
+func (h *Hooked) AfterRepositoryCreate(ctx context.Context) error {
+	h.calls = append(h.calls, "AfterCreate")
+	return nil
+}
+
+func (h *Hooked) BeforeRepositoryUpdate(ctx context.Context) error {
+	h.calls = append(h.calls, "BeforeUpdate")
+	return nil
+}
+
+func (h *Hooked) AfterRepositoryUpdate(ctx context.Context) error {
+	h.calls = append(h.calls, "AfterUpdate")
+	return nil
+}
+
+func (h *Hooked) BeforeRepositoryDelete(ctx context.Context) error {
+	h.calls = append(h.calls, "BeforeDelete")
+	return nil
+}
+
+func (h *Hooked) AfterRepositoryDelete(ctx context.Context) error {
+	h.calls = append(h.calls, "AfterDelete")
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	repo := InitRepository[Hooked](openDB(t, &Hooked{}))
+	ctx := context.Background()
+	model := &Hooked{Code: "a"}
+
+	if _, err := repo.Create(ctx, model); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(model.calls, []string{"BeforeCreate", "AfterCreate"}) {
+		t.Fatalf("got %v, want the create hooks called once", model.calls)
+	}
+
+	model.calls = nil
+
+	if err := repo.Update(ctx, model); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Delete(ctx, model); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(model.calls, []string{"BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete"}) {
+		t.Fatalf("got %v, want the update and delete hooks", model.calls)
+	}
+
+	failure := errors.New("failure")
+	failing := &Hooked{Code: "b", fail: failure}
+
+	if _, err := repo.Create(ctx, failing); !errors.Is(err, failure) {
+		t.Fatalf("got %v, want the error of the hook", err)
+	}
+
+	if n, err := repo.Count(ctx, "code = ?", "b"); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want the insert aborted", n, err)
+	}
+}
+
+func TestHooksMethods(t *testing.T) {
+	repo := InitRepository[Hooked](openDB(t, &Hooked{}))
+	ctx := context.Background()
+	create := []string{"BeforeCreate", "AfterCreate"}
+	update := []string{"BeforeUpdate", "AfterUpdate"}
+
+	// stored inserts model without running its hooks
+	stored := func(model *Hooked) error {
+		return repo.GetDB().Create(model).Error
+	}
+
+	tests := []struct {
+		name string
+		run  func(model *Hooked) error
+		want []string
+	}{
+		{"CreateOmit", func(model *Hooked) error {
+			_, err := repo.CreateOmit(ctx, model)
+			return err
+		}, create},
+		{"CreateReturning", func(model *Hooked) error {
+			_, err := repo.CreateReturning(ctx, model)
+			return err
+		}, create},
+		{"BatchCreate", func(model *Hooked) error {
+			_, err := repo.BatchCreate(ctx, []*Hooked{model})
+			return err
+		}, create},
+		{"BatchCreateInChunks", func(model *Hooked) error {
+			_, err := repo.BatchCreateInChunks(ctx, []*Hooked{model}, 10)
+			return err
+		}, create},
+		{"FirstOrCreate", func(model *Hooked) error {
+			_, _, err := repo.FirstOrCreate(ctx, model, Hooked{Code: model.Code})
+			return err
+		}, create},
+		{"UpdateOmit", func(model *Hooked) error {
+			if err := stored(model); err != nil {
+				return err
+			}
+
+			return repo.UpdateOmit(ctx, model)
+		}, update},
+		{"UpdateExisting", func(model *Hooked) error {
+			if err := stored(model); err != nil {
+				return err
+			}
+
+			_, err := repo.UpdateExisting(ctx, model)
+			return err
+		}, update},
+		{"Upsert", func(model *Hooked) error {
+			return repo.Upsert(ctx, model, []string{"code"}, nil)
+		}, nil},
+		{"BatchUpsert", func(model *Hooked) error {
+			_, err := repo.BatchUpsert(ctx, []*Hooked{model}, []string{"code"}, nil)
+			return err
+		}, nil},
+		{"CreateIgnore", func(model *Hooked) error {
+			_, err := repo.CreateIgnore(ctx, []*Hooked{model})
+			return err
+		}, nil},
+	}
+
+	for _, test := range tests {
+		model := &Hooked{Code: test.name}
+
+		if err := test.run(model); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(model.calls, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, model.calls, test.want)
+		}
+	}
+}
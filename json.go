@@ -0,0 +1,102 @@
+package regorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// jsonKey matches the keys allowed in JSON paths, they are written into the SQL so can't be bound
+var jsonKey = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// WhereJSON matches the records whose JSON column has value at path, path is the dot separated
+// keys leading to the value. The JSON extraction is built for the database dialect, Postgres jsonb,
+// MySQL JSON and SQLite JSON columns are supported. A nil value matches the JSON nulls and the missing keys:
+//
+//	repo.Find(ctx, &users, regorm.WhereJSON("settings", "notifications.email", true))
+//
+// column is validated against the model schema and ErrInvalidColumn is returned by the read method
+// if it doesn't exist, ErrInvalidJSONPath is returned if path has keys other than letters, digits and
+// underscores, ErrUnsupportedDialect for the other databases.
+func WhereJSON(column, path string, value interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		name, err := parseColumn(db, db.Statement.Model, column)
+
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		keys := strings.Split(path, ".")
+
+		for _, key := range keys {
+			if !jsonKey.MatchString(key) {
+				_ = db.AddError(fmt.Errorf("%w: %q", ErrInvalidJSONPath, path))
+				return db
+			}
+		}
+
+		col := clause.Column{Table: clause.CurrentTable, Name: name}
+
+		switch dialect := db.Dialector.Name(); dialect {
+		case "postgres":
+			extract := "?"
+
+			for i, key := range keys {
+				if i == len(keys)-1 && value == nil {
+					// ->> extracts JSON nulls as SQL NULL
+					extract += "->>'" + key + "'"
+				} else {
+					extract += "->'" + key + "'"
+				}
+			}
+
+			if value == nil {
+				return db.Where(extract+" IS NULL", col)
+			}
+
+			// the extracted jsonb value is compared to value as jsonb, so numbers and booleans match their JSON kind
+			data, err := json.Marshal(value)
+
+			if err != nil {
+				_ = db.AddError(fmt.Errorf("regorm: can't encode JSON value %v: %w", value, err))
+				return db
+			}
+
+			return db.Where(extract+" = ?::jsonb", col, string(data))
+		case "mysql":
+			extract := "JSON_EXTRACT(?, '$." + strings.Join(keys, ".") + "')"
+
+			if value == nil {
+				// JSON_EXTRACT returns SQL NULL for the missing keys and a JSON null for the JSON nulls
+				return db.Where("("+extract+" IS NULL OR JSON_TYPE("+extract+") = 'NULL')", col, col)
+			}
+
+			// the extracted JSON value is compared to value as JSON, so numbers and booleans match their JSON kind
+			data, err := json.Marshal(value)
+
+			if err != nil {
+				_ = db.AddError(fmt.Errorf("regorm: can't encode JSON value %v: %w", value, err))
+				return db
+			}
+
+			return db.Where(extract+" = CAST(? AS JSON)", col, string(data))
+		case "sqlite":
+			extract := "JSON_EXTRACT(?, '$." + strings.Join(keys, ".") + "')"
+
+			if value == nil {
+				// JSON_EXTRACT returns JSON nulls as SQL NULL, which = never matches
+				return db.Where(extract+" IS NULL", col)
+			}
+
+			return db.Where(extract+" = ?", col, value)
+		default:
+			_ = db.AddError(fmt.Errorf("%w: JSON queries on %s", ErrUnsupportedDialect, dialect))
+			return db
+		}
+	}
+}
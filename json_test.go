@@ -0,0 +1,97 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// Profile stores its settings as JSON
+type Profile struct {
+	ID       uint
+	Settings string
+}
+
+func (Profile) TableName() string { return "profiles" }
+
+func TestWhereJSON(t *testing.T) {
+	repo := InitRepository[Profile](openDB(t, &Profile{}))
+	ctx := context.Background()
+	profiles := []*Profile{
+		{Settings: `{"theme": "dark", "notifications": {"email": true, "limit": 5}}`},
+		{Settings: `{"theme": "light", "notifications": {"email": false, "limit": 10}}`},
+		{Settings: `{"theme": "dark", "notifications": {"email": null, "limit": 2.5}}`},
+	}
+
+	if _, err := repo.BatchCreate(ctx, profiles); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		value interface{}
+		want  uint
+	}{
+		{"theme", "light", profiles[1].ID},
+		{"notifications.email", true, profiles[0].ID},
+		{"notifications.limit", 10, profiles[1].ID},
+		{"notifications.limit", 2.5, profiles[2].ID},
+		{"notifications.email", false, profiles[1].ID},
+		{"notifications.email", nil, profiles[2].ID},
+	}
+
+	for _, test := range tests {
+		var found []Profile
+
+		if err := repo.Find(ctx, &found, WhereJSON("settings", test.path, test.value)); err != nil || len(found) != 1 || found[0].ID != test.want {
+			t.Errorf("%s = %v: got %+v, %v, want profile %d", test.path, test.value, found, err, test.want)
+		}
+	}
+
+	if err := repo.Find(ctx, &[]Profile{}, WhereJSON("settings", "theme') OR 1=1 --", "x")); !errors.Is(err, ErrInvalidJSONPath) {
+		t.Fatalf("got %v, want ErrInvalidJSONPath", err)
+	}
+
+	if err := repo.Find(ctx, &[]Profile{}, WhereJSON("missing", "theme", "x")); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestWhereJSONDialects(t *testing.T) {
+	// the SQL is explained by SQLite, which quotes strings with double quotes
+	tests := []struct {
+		dialect string
+		value   interface{}
+		want    string
+	}{
+		{"postgres", true, "SELECT * FROM `profiles` WHERE `profiles`.`settings`->'notifications'->'email' = \"true\"::jsonb"},
+		{"postgres", 5, "SELECT * FROM `profiles` WHERE `profiles`.`settings`->'notifications'->'email' = \"5\"::jsonb"},
+		{"postgres", "on", "SELECT * FROM `profiles` WHERE `profiles`.`settings`->'notifications'->'email' = \"\"\"on\"\"\"::jsonb"},
+		{"postgres", nil, "SELECT * FROM `profiles` WHERE `profiles`.`settings`->'notifications'->>'email' IS NULL"},
+		{"mysql", true, "SELECT * FROM `profiles` WHERE JSON_EXTRACT(`profiles`.`settings`, '$.notifications.email') = CAST(\"true\" AS JSON)"},
+		{"mysql", "on", "SELECT * FROM `profiles` WHERE JSON_EXTRACT(`profiles`.`settings`, '$.notifications.email') = CAST(\"\"\"on\"\"\" AS JSON)"},
+		{"mysql", 5, "SELECT * FROM `profiles` WHERE JSON_EXTRACT(`profiles`.`settings`, '$.notifications.email') = CAST(\"5\" AS JSON)"},
+		{"mysql", nil, "SELECT * FROM `profiles` WHERE (JSON_EXTRACT(`profiles`.`settings`, '$.notifications.email') IS NULL OR JSON_TYPE(JSON_EXTRACT(`profiles`.`settings`, '$.notifications.email')) = 'NULL')"},
+		{"sqlite", nil, "SELECT * FROM `profiles` WHERE JSON_EXTRACT(`profiles`.`settings`, '$.notifications.email') IS NULL"},
+	}
+
+	for _, test := range tests {
+		repo := InitRepository[Profile](openDialect(t, test.dialect, &Profile{}))
+
+		sql := repo.ExplainSQL(func(db *gorm.DB) *gorm.DB {
+			return WhereJSON("settings", "notifications.email", test.value)(db).Find(&[]Profile{})
+		})
+
+		if sql != test.want {
+			t.Errorf("%s %v: got %q, want %q", test.dialect, test.value, sql, test.want)
+		}
+	}
+
+	repo := InitRepository[Profile](openDialect(t, "sqlserver", &Profile{}))
+
+	if err := repo.Find(context.Background(), &[]Profile{}, WhereJSON("settings", "theme", "dark")); !errors.Is(err, ErrUnsupportedDialect) {
+		t.Fatalf("got %v, want ErrUnsupportedDialect", err)
+	}
+}
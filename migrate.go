@@ -0,0 +1,24 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// AutoMigrate creates or updates the table of the repository model to match its schema,
+// it never drops the unused columns. The table of WithTable and the schema of WithSchema
+// are migrated instead of the table of the model.
+func (r *Repository[T]) AutoMigrate() error {
+	return r.run(context.Background(), "AutoMigrate", func(ctx context.Context) (int64, error) {
+		db := r.Database
+
+		if r.tableName != "" || r.schemaName != "" {
+			if db = r.scopeTable(db.Session(&gorm.Session{})); db.Error != nil {
+				return 0, db.Error
+			}
+		}
+
+		return 0, db.AutoMigrate(new(T))
+	})
+}
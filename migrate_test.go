@@ -0,0 +1,49 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Widget isn't migrated by openDB
+type Widget struct {
+	ID   uint
+	Name string
+}
+
+func (Widget) TableName() string { return "widgets" }
+
+func TestAutoMigrate(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[Widget](db)
+
+	if err := repo.AutoMigrate(); err != nil || !db.Migrator().HasTable("widgets") {
+		t.Fatalf("got %v, want the widgets table", err)
+	}
+
+	if _, err := repo.Create(context.Background(), &Widget{Name: "gear"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatalf("got %v, want a migrated table left untouched", err)
+	}
+}
+
+func TestAutoMigrateTable(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[Widget](db).WithTable("widgets_2024")
+
+	if err := repo.AutoMigrate(); err != nil || !db.Migrator().HasTable("widgets_2024") || db.Migrator().HasTable("widgets") {
+		t.Fatalf("got %v, want only the widgets_2024 table", err)
+	}
+
+	if _, err := repo.Create(context.Background(), &Widget{Name: "gear"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitRepository[Widget](db).WithSchema("bad schema").AutoMigrate(); !errors.Is(err, ErrInvalidSchema) {
+		t.Fatalf("got %v, want ErrInvalidSchema", err)
+	}
+}
@@ -0,0 +1,1181 @@
+package regorm
+
+import (
+	"cmp"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// MockRepository is an in-memory IRepository to unit test services without a database,
+// records are matched on their primary key and First and Last pick them in primary key order:
+//
+//	repo := regorm.NewMockRepository[User]()
+//	service := NewUserService(repo)
+//
+// Conditions are limited to primary key values, the clauses built by the ID based methods
+// and column = value maps. The methods needing SQL like Raw, Upsert or the aggregates
+// return ErrNotSupported, as do the queries with other conditions or query options.
+// Soft deletes aren't emulated, records are always deleted.
+type MockRepository[T IBaseModel] struct {
+	mu      sync.Mutex
+	schema  *schema.Schema
+	err     error
+	records []T
+}
+
+// NewMockRepository returns an empty in-memory repository of T
+func NewMockRepository[T IBaseModel]() IRepository[T] {
+	s, err := schema.Parse(new(T), &sync.Map{}, schema.NamingStrategy{})
+
+	return &MockRepository[T]{schema: s, err: err}
+}
+
+// First finds the first record matching given conditions, model is left untouched if finds nothing
+func (m *MockRepository[T]) First(ctx context.Context, model *T, conds ...interface{}) error {
+	records, err := m.ordered(ctx, conds)
+
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	*model = records[0]
+
+	return nil
+}
+
+// FirstOrFail finds the first record matching given conditions, returns ErrNotFound if finds nothing
+func (m *MockRepository[T]) FirstOrFail(ctx context.Context, model *T, conds ...interface{}) error {
+	records, err := m.ordered(ctx, conds)
+
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return notFound(gorm.ErrRecordNotFound)
+	}
+
+	*model = records[0]
+
+	return nil
+}
+
+// FirstForUpdate finds the first record matching given conditions like First, records aren't locked
+func (m *MockRepository[T]) FirstForUpdate(ctx context.Context, model *T, conds ...interface{}) error {
+	return m.First(ctx, model, conds...)
+}
+
+// FirstForShare finds the first record matching given conditions like First, records aren't locked
+func (m *MockRepository[T]) FirstForShare(ctx context.Context, model *T, conds ...interface{}) error {
+	return m.First(ctx, model, conds...)
+}
+
+// Last finds the last record matching given conditions, model is left untouched if finds nothing
+func (m *MockRepository[T]) Last(ctx context.Context, model *T, conds ...interface{}) error {
+	records, err := m.ordered(ctx, conds)
+
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	*model = records[len(records)-1]
+
+	return nil
+}
+
+// LastOrFail finds the last record matching given conditions, returns ErrNotFound if finds nothing
+func (m *MockRepository[T]) LastOrFail(ctx context.Context, model *T, conds ...interface{}) error {
+	records, err := m.ordered(ctx, conds)
+
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return notFound(gorm.ErrRecordNotFound)
+	}
+
+	*model = records[len(records)-1]
+
+	return nil
+}
+
+// Take finds a record matching given conditions like First
+func (m *MockRepository[T]) Take(ctx context.Context, model *T, conds ...interface{}) error {
+	return m.First(ctx, model, conds...)
+}
+
+// TakeOrFail finds a record matching given conditions like FirstOrFail
+func (m *MockRepository[T]) TakeOrFail(ctx context.Context, model *T, conds ...interface{}) error {
+	return m.FirstOrFail(ctx, model, conds...)
+}
+
+// Find finds all the records matching given conditions
+func (m *MockRepository[T]) Find(ctx context.Context, models *[]T, conds ...interface{}) error {
+	records, err := m.find(ctx, conds)
+
+	if err != nil {
+		return err
+	}
+
+	*models = records
+
+	return nil
+}
+
+// FindOrFail finds all the records matching given conditions, returns ErrNotFound if finds nothing
+func (m *MockRepository[T]) FindOrFail(ctx context.Context, models *[]T, conds ...interface{}) error {
+	if err := m.Find(ctx, models, conds...); err != nil {
+		return err
+	}
+
+	if len(*models) == 0 {
+		return notFound(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// FindByID finds the record whose primary key equals id
+func (m *MockRepository[T]) FindByID(ctx context.Context, model *T, id interface{}) error {
+	return m.First(ctx, model, id)
+}
+
+// FindByIDOrFail finds the record whose primary key equals id, returns ErrNotFound if finds nothing
+func (m *MockRepository[T]) FindByIDOrFail(ctx context.Context, model *T, id interface{}) error {
+	return m.FirstOrFail(ctx, model, id)
+}
+
+// FindByIDs finds the records whose primary key is one of ids
+func (m *MockRepository[T]) FindByIDs(ctx context.Context, models *[]T, ids interface{}) error {
+	field, err := m.primaryField()
+
+	if err != nil {
+		return err
+	}
+
+	return m.Find(ctx, models, clause.IN{Column: clause.Column{Name: field.DBName}, Values: toSlice(ids)})
+}
+
+// FindByCompositeKey finds the record whose primary key columns equal keys
+func (m *MockRepository[T]) FindByCompositeKey(ctx context.Context, model *T, keys map[string]interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	if len(m.schema.PrimaryFields) == 0 || len(keys) != len(m.schema.PrimaryFields) {
+		return ErrInvalidCompositeKey
+	}
+
+	for name := range keys {
+		if field := m.schema.LookUpField(name); field == nil || !field.PrimaryKey {
+			return fmt.Errorf("%w: %q", ErrInvalidCompositeKey, name)
+		}
+	}
+
+	return m.First(ctx, model, keys)
+}
+
+// FirstBy finds the first record whose column equals value
+func (m *MockRepository[T]) FirstBy(ctx context.Context, model *T, column string, value interface{}) error {
+	return m.First(ctx, model, map[string]interface{}{column: value})
+}
+
+// FindBy finds all the records whose column equals value
+func (m *MockRepository[T]) FindBy(ctx context.Context, models *[]T, column string, value interface{}) error {
+	return m.Find(ctx, models, map[string]interface{}{column: value})
+}
+
+// FindByFilters finds all the records whose columns equal the values of filters, unknown columns are ignored
+func (m *MockRepository[T]) FindByFilters(ctx context.Context, models *[]T, filters map[string]interface{}) error {
+	known := make(map[string]interface{}, len(filters))
+
+	for column, value := range filters {
+		if m.schema == nil {
+			known[column] = value
+		} else if _, err := parseField(m.schema, column); err == nil {
+			known[column] = value
+		}
+	}
+
+	return m.Find(ctx, models, known)
+}
+
+// FirstWithTrashed finds the first record matching given conditions like First, as records are never soft deleted
+func (m *MockRepository[T]) FirstWithTrashed(ctx context.Context, model *T, conds ...interface{}) error {
+	return m.First(ctx, model, conds...)
+}
+
+// FindWithTrashed finds all the records matching given conditions like Find, as records are never soft deleted
+func (m *MockRepository[T]) FindWithTrashed(ctx context.Context, models *[]T, conds ...interface{}) error {
+	return m.Find(ctx, models, conds...)
+}
+
+// FirstOnlyTrashed returns ErrNotSupported
+func (m *MockRepository[T]) FirstOnlyTrashed(context.Context, *T, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// CountWithTrashed counts the records matching given conditions like Count, as records are never soft deleted
+func (m *MockRepository[T]) CountWithTrashed(ctx context.Context, conds ...interface{}) (int64, error) {
+	return m.Count(ctx, conds...)
+}
+
+// FindOnlyTrashed returns ErrNotSupported
+func (m *MockRepository[T]) FindOnlyTrashed(context.Context, *[]T, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// Create stores value, an integer primary key is assigned the next id if zero.
+// Returns gorm.ErrDuplicatedKey if a record with the same primary key is stored.
+func (m *MockRepository[T]) Create(ctx context.Context, model *T) (*T, error) {
+	if _, err := m.BatchCreate(ctx, []*T{model}); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FirstOrCreate finds the first record matching given conditions, if finds nothing stores value
+func (m *MockRepository[T]) FirstOrCreate(ctx context.Context, model *T, conds ...interface{}) (*T, bool, error) {
+	records, err := m.ordered(ctx, conds)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(records) > 0 {
+		*model = records[0]
+
+		return model, false, nil
+	}
+
+	if _, err := m.Create(ctx, model); err != nil {
+		return nil, false, err
+	}
+
+	return model, true, nil
+}
+
+// FirstOrInit finds the first record matching given conditions, value is left untouched if finds nothing
+func (m *MockRepository[T]) FirstOrInit(ctx context.Context, model *T, conds ...interface{}) error {
+	records, err := m.ordered(ctx, conds)
+
+	if err != nil {
+		return err
+	}
+
+	if len(records) > 0 {
+		*model = records[0]
+	}
+
+	return nil
+}
+
+// BatchCreate stores all the values like Create and returns rows affected
+func (m *MockRepository[T]) BatchCreate(ctx context.Context, models []*T) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := len(m.records)
+
+	for _, model := range models {
+		value := reflect.ValueOf(model).Elem()
+		m.assignID(ctx, value)
+
+		if m.indexOf(ctx, value) >= 0 {
+			// the batch is a single statement, a duplicate key stores none of its values
+			m.records = m.records[:stored]
+			return 0, gorm.ErrDuplicatedKey
+		}
+
+		m.records = append(m.records, *model)
+	}
+
+	return int64(len(models)), nil
+}
+
+// BatchCreateInChunks stores all the values like BatchCreate
+func (m *MockRepository[T]) BatchCreateInChunks(ctx context.Context, models []*T, _ int) (int64, error) {
+	return m.BatchCreate(ctx, models)
+}
+
+// CreateIgnore stores the values whose primary key isn't stored yet and returns how many it stored
+func (m *MockRepository[T]) CreateIgnore(ctx context.Context, models []*T) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows int64
+
+	for _, model := range models {
+		value := reflect.ValueOf(model).Elem()
+		m.assignID(ctx, value)
+
+		if m.indexOf(ctx, value) >= 0 {
+			continue
+		}
+
+		m.records = append(m.records, *model)
+		rows++
+	}
+
+	return rows, nil
+}
+
+// CreateOmit stores value like Create, omitted columns are stored as well
+func (m *MockRepository[T]) CreateOmit(ctx context.Context, model *T, _ ...string) (*T, error) {
+	return m.Create(ctx, model)
+}
+
+// CreateReturning stores value like Create, there are no database generated columns to read back
+func (m *MockRepository[T]) CreateReturning(ctx context.Context, model *T, _ ...string) (*T, error) {
+	return m.Create(ctx, model)
+}
+
+// Upsert returns ErrNotSupported
+func (m *MockRepository[T]) Upsert(context.Context, *T, []string, []string) error {
+	return ErrNotSupported
+}
+
+// UpsertReturning returns ErrNotSupported
+func (m *MockRepository[T]) UpsertReturning(context.Context, *T, []string, []string) (bool, error) {
+	return false, ErrNotSupported
+}
+
+// BatchUpsert returns ErrNotSupported
+func (m *MockRepository[T]) BatchUpsert(context.Context, []*T, []string, []string) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// Update replaces the record with the same primary key as value, stores value if there is none
+func (m *MockRepository[T]) Update(ctx context.Context, model *T) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.mu.Lock()
+	index := m.indexOf(ctx, reflect.ValueOf(model).Elem())
+
+	if index >= 0 {
+		m.records[index] = *model
+	}
+
+	m.mu.Unlock()
+
+	if index < 0 {
+		_, err := m.Create(ctx, model)
+
+		return err
+	}
+
+	return nil
+}
+
+// UpdateExisting replaces the record with the same primary key as value,
+// returns ErrNotFound if there is none
+func (m *MockRepository[T]) UpdateExisting(ctx context.Context, model *T) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.indexOf(ctx, reflect.ValueOf(model).Elem())
+
+	if index < 0 {
+		return 0, notFound(gorm.ErrRecordNotFound)
+	}
+
+	m.records[index] = *model
+
+	return 1, nil
+}
+
+// UpdateOmit replaces the record like Update, omitted columns are replaced as well
+func (m *MockRepository[T]) UpdateOmit(ctx context.Context, model *T, _ ...string) error {
+	return m.Update(ctx, model)
+}
+
+// UpdateColumns returns ErrNotSupported
+func (m *MockRepository[T]) UpdateColumns(context.Context, interface{}, map[string]interface{}) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// UpdateWhere returns ErrNotSupported
+func (m *MockRepository[T]) UpdateWhere(context.Context, interface{}, map[string]interface{}) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// Increment returns ErrNotSupported
+func (m *MockRepository[T]) Increment(context.Context, interface{}, string, int64) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// Decrement returns ErrNotSupported
+func (m *MockRepository[T]) Decrement(context.Context, interface{}, string, int64) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// Delete removes the record with the same primary key as value and returns rows affected
+func (m *MockRepository[T]) Delete(ctx context.Context, model *T) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.indexOf(ctx, reflect.ValueOf(model).Elem())
+
+	if index < 0 {
+		return 0, nil
+	}
+
+	m.records = append(m.records[:index], m.records[index+1:]...)
+
+	return 1, nil
+}
+
+// DeleteByID removes the record whose primary key equals id and returns rows affected
+func (m *MockRepository[T]) DeleteByID(ctx context.Context, id interface{}) (int64, error) {
+	var model T
+
+	if err := m.First(ctx, &model, id); err != nil {
+		return 0, err
+	}
+
+	return m.Delete(ctx, &model)
+}
+
+// ForceDelete removes the record like Delete
+func (m *MockRepository[T]) ForceDelete(ctx context.Context, model *T) (int64, error) {
+	return m.Delete(ctx, model)
+}
+
+// Restore returns ErrNotSupported
+func (m *MockRepository[T]) Restore(context.Context, *T) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// DeleteWhere returns ErrNotSupported
+func (m *MockRepository[T]) DeleteWhere(context.Context, interface{}) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// DeleteInChunks returns ErrNotSupported
+func (m *MockRepository[T]) DeleteInChunks(context.Context, interface{}, int) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// Count counts the records matching given conditions
+func (m *MockRepository[T]) Count(ctx context.Context, conds ...interface{}) (int64, error) {
+	records, err := m.find(ctx, conds)
+
+	return int64(len(records)), err
+}
+
+// Exists reports whether any record matches given conditions
+func (m *MockRepository[T]) Exists(ctx context.Context, conds ...interface{}) (bool, error) {
+	count, err := m.Count(ctx, conds...)
+
+	return count > 0, err
+}
+
+// ExistsBy reports whether any record has column equal to value
+func (m *MockRepository[T]) ExistsBy(ctx context.Context, column string, value interface{}) (bool, error) {
+	return m.Exists(ctx, map[string]interface{}{column: value})
+}
+
+// Sum returns ErrNotSupported
+func (m *MockRepository[T]) Sum(context.Context, string, ...interface{}) (float64, error) {
+	return 0, ErrNotSupported
+}
+
+// Avg returns ErrNotSupported
+func (m *MockRepository[T]) Avg(context.Context, string, ...interface{}) (float64, error) {
+	return 0, ErrNotSupported
+}
+
+// Min returns ErrNotSupported
+func (m *MockRepository[T]) Min(context.Context, string, ...interface{}) (float64, error) {
+	return 0, ErrNotSupported
+}
+
+// Max returns ErrNotSupported
+func (m *MockRepository[T]) Max(context.Context, string, ...interface{}) (float64, error) {
+	return 0, ErrNotSupported
+}
+
+// GroupCount returns ErrNotSupported
+func (m *MockRepository[T]) GroupCount(context.Context, string, ...interface{}) (map[string]int64, error) {
+	return nil, ErrNotSupported
+}
+
+// Distinct returns ErrNotSupported
+func (m *MockRepository[T]) Distinct(context.Context, string, interface{}, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// Pluck returns ErrNotSupported
+func (m *MockRepository[T]) Pluck(context.Context, string, interface{}, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// PluckDistinct returns ErrNotSupported
+func (m *MockRepository[T]) PluckDistinct(context.Context, string, interface{}, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// ScanInto returns ErrNotSupported
+func (m *MockRepository[T]) ScanInto(context.Context, interface{}, ...QueryOption) error {
+	return ErrNotSupported
+}
+
+// FindMaps returns ErrNotSupported
+func (m *MockRepository[T]) FindMaps(context.Context, *[]map[string]interface{}, ...QueryOption) error {
+	return ErrNotSupported
+}
+
+// Raw returns ErrNotSupported
+func (m *MockRepository[T]) Raw(context.Context, interface{}, string, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// Exec returns ErrNotSupported
+func (m *MockRepository[T]) Exec(context.Context, string, ...interface{}) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// FindInBatches finds the records matching given conditions and calls fn with batches of up to batchSize records
+func (m *MockRepository[T]) FindInBatches(ctx context.Context, batchSize int, fn func(batch []T) error, conds ...interface{}) error {
+	records, err := m.find(ctx, conds)
+
+	if err != nil {
+		return err
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(records)
+	}
+
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+
+		if err := fn(records[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stream sends the records matching given conditions one at a time
+func (m *MockRepository[T]) Stream(ctx context.Context, conds ...interface{}) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(items)
+		defer close(errs)
+
+		records, err := m.find(ctx, conds)
+
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for _, record := range records {
+			select {
+			case items <- record:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return items, errs
+}
+
+// OpenCursor returns ErrNotSupported
+func (m *MockRepository[T]) OpenCursor(context.Context, ...interface{}) (*Cursor[T], error) {
+	return nil, ErrNotSupported
+}
+
+// Paginate finds the records of the page matching given conditions
+func (m *MockRepository[T]) Paginate(ctx context.Context, models *[]T, page, pageSize int, conds ...interface{}) error {
+	records, err := m.find(ctx, conds)
+
+	if err != nil {
+		return err
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	start := min((page-1)*pageSize, len(records))
+	end := min(start+pageSize, len(records))
+	*models = records[start:end]
+
+	return nil
+}
+
+// FindPaginated finds the records of the page matching given conditions along with the pagination metadata
+func (m *MockRepository[T]) FindPaginated(ctx context.Context, page, pageSize int, conds ...interface{}) (*Page[T], error) {
+	total, err := m.Count(ctx, conds...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	result := &Page[T]{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+
+	if err := m.Paginate(ctx, &result.Items, page, pageSize, conds...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAndCount finds the records of the page matching given conditions and returns the count of the matching records
+func (m *MockRepository[T]) FindAndCount(ctx context.Context, models *[]T, page, pageSize int, conds ...interface{}) (int64, error) {
+	total, err := m.Count(ctx, conds...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := m.Paginate(ctx, models, page, pageSize, conds...); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// FindAfter returns ErrNotSupported
+func (m *MockRepository[T]) FindAfter(context.Context, *[]T, string, interface{}, int, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// Search returns ErrNotSupported
+func (m *MockRepository[T]) Search(context.Context, *[]T, []string, string, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// RunInTransaction runs fn with the mock itself, the records are restored if fn returns an error or panics
+func (m *MockRepository[T]) RunInTransaction(ctx context.Context, fn func(txRepo IRepository[T]) error) (err error) {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.mu.Lock()
+	snapshot := append([]T(nil), m.records...)
+	m.mu.Unlock()
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			m.mu.Lock()
+			m.records = snapshot
+			m.mu.Unlock()
+
+			if p != nil {
+				panic(p)
+			}
+		}
+	}()
+
+	return fn(m)
+}
+
+// WithTx returns the mock itself
+func (m *MockRepository[T]) WithTx(*gorm.DB) IRepository[T] {
+	return m
+}
+
+// Begin returns ErrNotSupported
+func (m *MockRepository[T]) Begin(context.Context) (IRepository[T], error) {
+	return nil, ErrNotSupported
+}
+
+// Commit returns ErrNotSupported
+func (m *MockRepository[T]) Commit() error {
+	return ErrNotSupported
+}
+
+// Rollback returns ErrNotSupported
+func (m *MockRepository[T]) Rollback() error {
+	return ErrNotSupported
+}
+
+// SavePoint returns ErrNotSupported
+func (m *MockRepository[T]) SavePoint(string) error {
+	return ErrNotSupported
+}
+
+// RollbackTo returns ErrNotSupported
+func (m *MockRepository[T]) RollbackTo(string) error {
+	return ErrNotSupported
+}
+
+// SubQuery returns nil as the mock has no database
+func (m *MockRepository[T]) SubQuery(...QueryOption) *gorm.DB {
+	return nil
+}
+
+// AutoMigrate does nothing as the mock has no table
+func (m *MockRepository[T]) AutoMigrate() error {
+	return m.err
+}
+
+// Ping does nothing as the mock has no database
+func (m *MockRepository[T]) Ping(context.Context) error {
+	return m.err
+}
+
+// ConfigurePool does nothing as the mock has no database
+func (m *MockRepository[T]) ConfigurePool(int, int, time.Duration) error {
+	return m.err
+}
+
+// ExplainSQL returns an empty string as the mock has no database
+func (m *MockRepository[T]) ExplainSQL(func(db *gorm.DB) *gorm.DB) string {
+	return ""
+}
+
+// CountAssociation returns ErrNotSupported
+func (m *MockRepository[T]) CountAssociation(context.Context, *T, string) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+// FindAssociated returns ErrNotSupported
+func (m *MockRepository[T]) FindAssociated(context.Context, *T, string, interface{}) error {
+	return ErrNotSupported
+}
+
+// AppendAssociation returns ErrNotSupported
+func (m *MockRepository[T]) AppendAssociation(context.Context, *T, string, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// ReplaceAssociation returns ErrNotSupported
+func (m *MockRepository[T]) ReplaceAssociation(context.Context, *T, string, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// DeleteAssociation returns ErrNotSupported
+func (m *MockRepository[T]) DeleteAssociation(context.Context, *T, string, ...interface{}) error {
+	return ErrNotSupported
+}
+
+// SetLogger does nothing
+func (m *MockRepository[T]) SetLogger(Logger) {}
+
+// SetTracer does nothing
+func (m *MockRepository[T]) SetTracer(Tracer) {}
+
+// SetMetrics does nothing
+func (m *MockRepository[T]) SetMetrics(MetricsCollector) {}
+
+// SetSlowThreshold does nothing
+func (m *MockRepository[T]) SetSlowThreshold(time.Duration) {}
+
+// OnSlowQuery does nothing
+func (m *MockRepository[T]) OnSlowQuery(func(op string, d time.Duration)) {}
+
+// SetSoftDeleteColumn does nothing, records are always deleted
+func (m *MockRepository[T]) SetSoftDeleteColumn(string) {}
+
+// WithActor returns the mock itself, actors aren't stamped
+func (m *MockRepository[T]) WithActor(interface{}) IRepository[T] {
+	return m
+}
+
+// WithTenant returns a mock failing with ErrNotSupported, records aren't scoped to tenants
+func (m *MockRepository[T]) WithTenant(string, interface{}) IRepository[T] {
+	return m.unsupported("WithTenant")
+}
+
+// Clone returns a mock failing with ErrNotSupported, the mock has no database to run on
+func (m *MockRepository[T]) Clone(*gorm.DB) IRepository[T] {
+	return m.unsupported("Clone")
+}
+
+// WithTable returns a mock failing with ErrNotSupported, records are stored in a single table
+func (m *MockRepository[T]) WithTable(string) IRepository[T] {
+	return m.unsupported("WithTable")
+}
+
+// WithSchema returns a mock failing with ErrNotSupported, records are stored in a single table
+func (m *MockRepository[T]) WithSchema(string) IRepository[T] {
+	return m.unsupported("WithSchema")
+}
+
+// WithScope returns a mock failing with ErrNotSupported, scopes need a database
+func (m *MockRepository[T]) WithScope(func(*gorm.DB) *gorm.DB) IRepository[T] {
+	return m.unsupported("WithScope")
+}
+
+// MaskColumns does nothing, records are read with all their columns
+func (m *MockRepository[T]) MaskColumns(...string) {}
+
+// SetEncryption does nothing, records are stored in memory in plaintext
+func (m *MockRepository[T]) SetEncryption(FieldEncryptor, ...string) {}
+
+// SetDebug does nothing, the mock runs no queries
+func (m *MockRepository[T]) SetDebug(bool) {}
+
+// SetNPlusOneThreshold does nothing
+func (m *MockRepository[T]) SetNPlusOneThreshold(int) {}
+
+// OnNPlusOne does nothing
+func (m *MockRepository[T]) OnNPlusOne(func(query string, count int)) {}
+
+// SetDefaultTimeout does nothing, the mock runs no queries
+func (m *MockRepository[T]) SetDefaultTimeout(time.Duration) {}
+
+// SetTimestampColumns does nothing
+func (m *MockRepository[T]) SetTimestampColumns(string, string) {}
+
+// SetReadWriteSplitting does nothing
+func (m *MockRepository[T]) SetReadWriteSplitting(bool) {}
+
+// WithReadOnly returns the mock itself
+func (m *MockRepository[T]) WithReadOnly() IRepository[T] {
+	return m
+}
+
+// SetCache does nothing
+func (m *MockRepository[T]) SetCache(Cache, time.Duration) {}
+
+// WithRetry returns the mock itself, it never fails with transient errors
+func (m *MockRepository[T]) WithRetry(int, time.Duration) IRepository[T] {
+	return m
+}
+
+// SetRetryable does nothing
+func (m *MockRepository[T]) SetRetryable(func(err error) bool) {}
+
+// GetDB returns nil as the mock has no database
+func (m *MockRepository[T]) GetDB() *gorm.DB {
+	return nil
+}
+
+// unsupported returns an empty mock whose methods fail with ErrNotSupported,
+// for the settings the mock can't emulate rather than silently ignoring them
+func (m *MockRepository[T]) unsupported(setting string) IRepository[T] {
+	return &MockRepository[T]{schema: m.schema, err: fmt.Errorf("%w: %s", ErrNotSupported, setting)}
+}
+
+// find returns a copy of the records matching conds
+func (m *MockRepository[T]) find(ctx context.Context, conds []interface{}) ([]T, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	match, err := m.matcher(conds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records := []T{}
+
+	for i := range m.records {
+		if match(ctx, reflect.ValueOf(&m.records[i]).Elem()) {
+			records = append(records, m.records[i])
+		}
+	}
+
+	return records, nil
+}
+
+// ordered returns a copy of the records matching conds sorted by primary key
+func (m *MockRepository[T]) ordered(ctx context.Context, conds []interface{}) ([]T, error) {
+	records, err := m.find(ctx, conds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := reflect.ValueOf(&records[i]).Elem(), reflect.ValueOf(&records[j]).Elem()
+
+		for _, field := range m.schema.PrimaryFields {
+			aKey, _ := field.ValueOf(ctx, a)
+			bKey, _ := field.ValueOf(ctx, b)
+
+			if c := compareValues(aKey, bKey); c != 0 {
+				return c < 0
+			}
+		}
+
+		return false
+	})
+
+	return records, nil
+}
+
+// matcher returns the func reporting whether a record matches conds,
+// returns ErrNotSupported for the conditions the mock can't evaluate
+func (m *MockRepository[T]) matcher(conds []interface{}) (func(ctx context.Context, record reflect.Value) bool, error) {
+	if len(conds) == 0 {
+		return func(context.Context, reflect.Value) bool { return true }, nil
+	}
+
+	if len(conds) > 1 {
+		return nil, fmt.Errorf("%w: %d conditions", ErrNotSupported, len(conds))
+	}
+
+	switch cond := conds[0].(type) {
+	case clause.Eq:
+		return m.columnMatcher(cond.Column, []interface{}{cond.Value})
+	case clause.IN:
+		return m.columnMatcher(cond.Column, cond.Values)
+	case clause.AndConditions:
+		return m.allMatcher(toSlice(cond.Exprs))
+	case map[string]interface{}:
+		exprs := make([]interface{}, 0, len(cond))
+
+		for column, value := range cond {
+			exprs = append(exprs, clause.Eq{Column: column, Value: value})
+		}
+
+		return m.allMatcher(exprs)
+	case string, QueryOption, func(*gorm.DB) *gorm.DB, Specification, clause.Expression:
+		return nil, fmt.Errorf("%w: condition %v", ErrNotSupported, cond)
+	}
+
+	if _, ok := conds[0].(driver.Valuer); !ok && reflect.Indirect(reflect.ValueOf(conds[0])).Kind() == reflect.Struct {
+		return nil, fmt.Errorf("%w: condition %v", ErrNotSupported, conds[0])
+	}
+
+	field, err := m.primaryField()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// primary key values, or a slice of them
+	return m.columnMatcher(field.DBName, toSlice(conds[0]))
+}
+
+// allMatcher returns the func reporting whether a record matches every one of conds
+func (m *MockRepository[T]) allMatcher(conds []interface{}) (func(ctx context.Context, record reflect.Value) bool, error) {
+	matchers := make([]func(ctx context.Context, record reflect.Value) bool, 0, len(conds))
+
+	for _, cond := range conds {
+		match, err := m.matcher([]interface{}{cond})
+
+		if err != nil {
+			return nil, err
+		}
+
+		matchers = append(matchers, match)
+	}
+
+	return func(ctx context.Context, record reflect.Value) bool {
+		for _, match := range matchers {
+			if !match(ctx, record) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+// columnMatcher returns the func reporting whether the column of a record equals one of values
+func (m *MockRepository[T]) columnMatcher(column interface{}, values []interface{}) (func(ctx context.Context, record reflect.Value) bool, error) {
+	name, ok := column.(string)
+
+	if c, isColumn := column.(clause.Column); isColumn {
+		name, ok = c.Name, true
+	}
+
+	if !ok || m.schema == nil {
+		return nil, fmt.Errorf("%w: column %v", ErrNotSupported, column)
+	}
+
+	field, err := parseField(m.schema, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, record reflect.Value) bool {
+		fieldValue, _ := field.ValueOf(ctx, record)
+
+		for _, value := range values {
+			if sameValue(fieldValue, value) {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+// primaryField returns the single primary key field of the model
+func (m *MockRepository[T]) primaryField() (*schema.Field, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if m.schema.PrioritizedPrimaryField == nil {
+		return nil, ErrInvalidPrimaryKey
+	}
+
+	return m.schema.PrioritizedPrimaryField, nil
+}
+
+// indexOf returns the index of the record with the same primary key as value, -1 if there is none
+func (m *MockRepository[T]) indexOf(ctx context.Context, value reflect.Value) int {
+	if len(m.schema.PrimaryFields) == 0 {
+		return -1
+	}
+
+	for i := range m.records {
+		record := reflect.ValueOf(&m.records[i]).Elem()
+		same := true
+
+		for _, field := range m.schema.PrimaryFields {
+			recordKey, _ := field.ValueOf(ctx, record)
+			key, _ := field.ValueOf(ctx, value)
+
+			if !sameValue(recordKey, key) {
+				same = false
+				break
+			}
+		}
+
+		if same {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// assignID sets a zero integer primary key of value to the next id
+func (m *MockRepository[T]) assignID(ctx context.Context, value reflect.Value) {
+	field := m.schema.PrioritizedPrimaryField
+
+	if field == nil || len(m.schema.PrimaryFields) > 1 {
+		return
+	}
+
+	if _, zero := field.ValueOf(ctx, value); !zero {
+		return
+	}
+
+	var next int64
+
+	for i := range m.records {
+		key, _ := field.ValueOf(ctx, reflect.ValueOf(&m.records[i]).Elem())
+		id := reflect.ValueOf(key)
+
+		switch id.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			next = max(next, id.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			next = max(next, int64(id.Uint()))
+		default:
+			return
+		}
+	}
+
+	switch field.FieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_ = field.Set(ctx, value, next+1)
+	}
+}
+
+// sameValue reports whether a field value equals value, numbers of different types are compared by value
+func sameValue(fieldValue, value interface{}) bool {
+	a, b := reflect.ValueOf(fieldValue), reflect.ValueOf(value)
+
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if a.Type() == b.Type() {
+		return reflect.DeepEqual(fieldValue, value)
+	}
+
+	if isNumber(a.Kind()) && isNumber(b.Kind()) && b.Type().ConvertibleTo(a.Type()) {
+		converted := b.Convert(a.Type())
+
+		return converted.Convert(b.Type()).Interface() == b.Interface() && converted.Interface() == a.Interface()
+	}
+
+	return false
+}
+
+// compareValues returns -1, 0 or 1 as a is less than, equal to or greater than b,
+// values which are neither numbers nor strings are compared by their formatting
+func compareValues(a, b interface{}) int {
+	x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	switch {
+	case !x.IsValid() || !y.IsValid():
+		return cmp.Compare(boolInt(x.IsValid()), boolInt(y.IsValid()))
+	case x.CanInt() && y.CanInt():
+		return cmp.Compare(x.Int(), y.Int())
+	case x.CanUint() && y.CanUint():
+		return cmp.Compare(x.Uint(), y.Uint())
+	case x.CanFloat() && y.CanFloat():
+		return cmp.Compare(x.Float(), y.Float())
+	case x.Kind() == reflect.String && y.Kind() == reflect.String:
+		return cmp.Compare(x.String(), y.String())
+	}
+
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// boolInt returns 1 if b is true, 0 otherwise
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// isNumber reports whether kind is an integer or floating point kind
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
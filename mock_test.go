@@ -0,0 +1,122 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestMockRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockRepository[User]()
+	users := seed(t, repo, "alice", "bob")
+
+	if users[0].ID != 1 || users[1].ID != 2 {
+		t.Fatalf("got ids %d, %d, want 1, 2", users[0].ID, users[1].ID)
+	}
+
+	var found User
+
+	if err := repo.FirstOrFail(ctx, &found, users[1].ID); err != nil || found.Name != "bob" {
+		t.Fatalf("got %+v, %v, want bob", found, err)
+	}
+
+	found.Age = 40
+
+	if err := repo.Update(ctx, &found); err != nil {
+		t.Fatal(err)
+	}
+
+	var updated User
+
+	if err := repo.FirstOrFail(ctx, &updated, map[string]interface{}{"name": "bob"}); err != nil || updated.Age != 40 {
+		t.Fatalf("got %+v, %v, want age 40", updated, err)
+	}
+
+	if _, err := repo.Delete(ctx, users[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := repo.Count(ctx)
+
+	if err != nil || count != 1 {
+		t.Fatalf("got %d, %v, want 1", count, err)
+	}
+
+	if err := repo.FirstOrFail(ctx, &found, users[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+
+	if _, err := repo.Create(ctx, &User{ID: users[1].ID}); err == nil {
+		t.Fatal("got no error, want a duplicated key")
+	}
+
+	if _, err := repo.BatchCreate(ctx, []*User{{Name: "carol"}, {ID: users[1].ID}}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("got %v, want a duplicated key", err)
+	}
+
+	if count, err = repo.Count(ctx); err != nil || count != 1 {
+		t.Fatalf("got %d, %v, want the batch stored none of its values", count, err)
+	}
+
+	if err := repo.Find(ctx, &[]User{}, "name = ?"); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("got %v, want ErrNotSupported", err)
+	}
+}
+
+func TestMockRepositoryOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockRepository[User]()
+
+	for _, user := range []*User{{ID: 3, Name: "carol"}, {ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}} {
+		if _, err := repo.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var first, last User
+
+	if err := repo.First(ctx, &first); err != nil || first.ID != 1 {
+		t.Fatalf("got %+v, %v, want id 1", first, err)
+	}
+
+	if err := repo.Last(ctx, &last); err != nil || last.ID != 3 {
+		t.Fatalf("got %+v, %v, want id 3", last, err)
+	}
+
+	if err := repo.LastOrFail(ctx, &last, []uint{1, 2}); err != nil || last.ID != 2 {
+		t.Fatalf("got %+v, %v, want id 2", last, err)
+	}
+}
+
+func TestMockRepositoryUnsupported(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockRepository[User]()
+	seed(t, repo, "alice")
+
+	repos := map[string]IRepository[User]{
+		"WithTenant": repo.WithTenant("tenant_id", 1),
+		"WithScope":  repo.WithScope(nil),
+		"Clone":      repo.Clone(nil),
+		"WithTable":  repo.WithTable("archived_users"),
+		"WithSchema": repo.WithSchema("archive"),
+	}
+
+	for name, derived := range repos {
+		var user User
+
+		if err := derived.First(ctx, &user); !errors.Is(err, ErrNotSupported) {
+			t.Errorf("%s: got %+v, %v, want ErrNotSupported", name, user, err)
+		}
+
+		if _, err := derived.Create(ctx, &User{Name: "bob"}); !errors.Is(err, ErrNotSupported) {
+			t.Errorf("%s: got %v, want ErrNotSupported", name, err)
+		}
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 1 {
+		t.Fatalf("got %d, %v, want 1", count, err)
+	}
+}
@@ -0,0 +1,192 @@
+package regorm
+
+import (
+	"context"
+	"time"
+)
+
+// Logger observes the operations of repositories, set it with SetLogger:
+//
+//	type slogLogger struct{}
+//
+//	func (slogLogger) Observe(op string, table string, dur time.Duration, rows int64, err error) {
+//		slog.Info("regorm", "op", op, "table", table, "dur", dur, "rows", rows, "err", err)
+//	}
+type Logger interface {
+	Observe(op string, table string, dur time.Duration, rows int64, err error) // Called after each operation
+}
+
+// NopLogger is the default Logger of repositories which discards everything
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+// Observe implements Logger
+func (nopLogger) Observe(string, string, time.Duration, int64, error) {}
+
+// SetLogger sets the logger observing the operations of repository, nil resets it to NopLogger
+func (r *Repository[T]) SetLogger(logger Logger) {
+	r.logger = logger
+}
+
+// Tracer starts spans for the operations of repositories, set it with SetTracer.
+// It lets repositories be traced with OpenTelemetry or any other tracing library:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+//		ctx, span := t.tracer.Start(ctx, name)
+//
+//		return ctx, func(err error) {
+//			if err != nil {
+//				span.RecordError(err)
+//				span.SetStatus(codes.Error, err.Error())
+//			}
+//			span.End()
+//		}
+//	}
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, func(error)) // Start span name, the returned func ends it
+}
+
+// SetTracer sets the tracer starting a span named like regorm.First for each operation of repository, nil disables tracing
+func (r *Repository[T]) SetTracer(tracer Tracer) {
+	r.tracer = tracer
+}
+
+// MetricsCollector records metrics of the operations of repositories, set it with SetMetrics.
+// It lets repositories export metrics to Prometheus or any other monitoring system:
+//
+//	type promMetrics struct {
+//		ops     *prometheus.CounterVec
+//		latency *prometheus.HistogramVec
+//	}
+//
+//	func (m promMetrics) IncOp(op, table string, success bool) {
+//		m.ops.WithLabelValues(op, table, strconv.FormatBool(success)).Inc()
+//	}
+//
+//	func (m promMetrics) ObserveLatency(op, table string, d time.Duration) {
+//		m.latency.WithLabelValues(op, table).Observe(d.Seconds())
+//	}
+type MetricsCollector interface {
+	IncOp(op, table string, success bool)             // Count an operation, success is false when it failed
+	ObserveLatency(op, table string, d time.Duration) // Record the duration of an operation
+}
+
+// SetMetrics sets the collector recording metrics of each operation of repository, nil disables metrics
+func (r *Repository[T]) SetMetrics(metrics MetricsCollector) {
+	r.metrics = metrics
+}
+
+// SetSlowThreshold sets the duration above which operations of repository are reported to the OnSlowQuery callback,
+// zero disables slow query detection
+func (r *Repository[T]) SetSlowThreshold(threshold time.Duration) {
+	r.slowThreshold = threshold
+}
+
+// OnSlowQuery sets the callback called with the operations of repository slower than the slow threshold
+func (r *Repository[T]) OnSlowQuery(fn func(op string, d time.Duration)) {
+	r.onSlowQuery = fn
+}
+
+// operationKey is the context key of the running operation
+type operationKey struct{}
+
+// operation is a running repository operation
+type operation struct {
+	name string
+	rows int64
+	repo interface{}
+}
+
+// run runs fn as the repository operation op, fn returns rows affected.
+// Operations run by other operations of the same repository, like FindByID running First, are part
+// of the outer one and aren't observed on their own. Operations of other repositories, like the ones
+// run by hooks, are observed by their repository.
+func (r *Repository[T]) run(ctx context.Context, op string, fn func(ctx context.Context) (int64, error)) error {
+	if outer, ok := ctx.Value(operationKey{}).(*operation); ok && outer.repo == r {
+		rows, err := fn(ctx)
+		outer.rows += rows
+
+		return err
+	}
+
+	ctx, cancel := r.withTimeout(ctx, op)
+	defer cancel()
+
+	current := &operation{name: op, repo: r}
+	ctx = context.WithValue(ctx, operationKey{}, current)
+
+	var endSpan func(error)
+
+	if r.tracer != nil {
+		ctx, endSpan = r.tracer.StartSpan(ctx, "regorm."+op)
+	}
+
+	start := time.Now()
+	rows, err := r.attempt(ctx, op, current, fn)
+	dur := time.Since(start)
+
+	if err == nil && r.cache != nil && writeOperations[op] {
+		r.invalidateCache()
+	}
+
+	if endSpan != nil {
+		endSpan(err)
+	}
+
+	table := r.table()
+
+	if r.metrics != nil {
+		r.metrics.IncOp(op, table, err == nil)
+		r.metrics.ObserveLatency(op, table, dur)
+	}
+
+	if r.onSlowQuery != nil && r.slowThreshold > 0 && dur > r.slowThreshold {
+		r.onSlowQuery(op, dur)
+	}
+
+	r.getLogger().Observe(op, table, dur, current.rows+rows, err)
+
+	return err
+}
+
+// runRows runs fn as the repository operation op like run and returns the rows affected by fn
+func (r *Repository[T]) runRows(ctx context.Context, op string, fn func(ctx context.Context) (int64, error)) (int64, error) {
+	var rows int64
+	err := r.run(ctx, op, func(ctx context.Context) (int64, error) {
+		var err error
+		rows, err = fn(ctx)
+
+		return rows, err
+	})
+
+	return rows, err
+}
+
+// getLogger returns the logger of repository, NopLogger if none is set
+func (r *Repository[T]) getLogger() Logger {
+	if r.logger == nil {
+		return NopLogger
+	}
+
+	return r.logger
+}
+
+// table returns the table name of the repository, the table set WithTable or else the table of the model,
+// prefixed with the schema set WithSchema
+func (r *Repository[T]) table() string {
+	var model T
+	table := model.TableName()
+
+	if r.tableName != "" {
+		table = r.tableName
+	}
+
+	if r.schemaName != "" {
+		return r.schemaName + "." + table
+	}
+
+	return table
+}
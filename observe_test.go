@@ -0,0 +1,282 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeLogger records the operations it observes
+type fakeLogger struct {
+	mu  sync.Mutex
+	ops []string
+}
+
+func (l *fakeLogger) Observe(op string, table string, dur time.Duration, rows int64, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ops = append(l.ops, op+" "+table)
+}
+
+func (l *fakeLogger) observed() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]string{}, l.ops...)
+}
+
+func TestLogger(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	logger := &fakeLogger{}
+	repo.SetLogger(logger)
+	ctx := context.Background()
+
+	var user User
+
+	if _, err := repo.Create(ctx, &User{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.First(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.FindByID(ctx, &user, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// FindByID runs First, which is part of it
+	if ops := logger.observed(); !reflect.DeepEqual(ops, []string{"Create users", "First users", "FindByID users"}) {
+		t.Fatalf("got %v", ops)
+	}
+}
+
+func TestLoggerTransaction(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	logger := &fakeLogger{}
+	repo.SetLogger(logger)
+	ctx := context.Background()
+
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.ConfigurePool(10, 5, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := repo.Begin(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.SavePoint("sp"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.RollbackTo("sp"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if tx, err = repo.Begin(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"AutoMigrate users", "ConfigurePool users", "Begin users", "SavePoint users", "RollbackTo users",
+		"Rollback users", "Begin users", "Commit users",
+	}
+
+	if ops := logger.observed(); !reflect.DeepEqual(ops, want) {
+		t.Fatalf("got %v, want %v", ops, want)
+	}
+}
+
+// stubTracer records the spans it starts and the errors they end with
+type stubTracer struct {
+	spans []string
+	errs  []error
+}
+
+func (s *stubTracer) StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	s.spans = append(s.spans, name)
+
+	return ctx, func(err error) {
+		s.errs = append(s.errs, err)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	tracer := &stubTracer{}
+	repo.SetTracer(tracer)
+	ctx := context.Background()
+
+	var user User
+
+	if _, err := repo.Create(ctx, &User{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = repo.FindByIDOrFail(ctx, &user, 42)
+
+	if !reflect.DeepEqual(tracer.spans, []string{"regorm.Create", "regorm.FindByIDOrFail"}) {
+		t.Fatalf("got %v, want a span per call", tracer.spans)
+	}
+
+	if len(tracer.errs) != 2 || tracer.errs[0] != nil || !errors.Is(tracer.errs[1], ErrNotFound) {
+		t.Fatalf("got %v, want the spans ended with the errors", tracer.errs)
+	}
+}
+
+// fakeMetrics records the operations and latencies it's called with
+type fakeMetrics struct {
+	ops       []string
+	latencies []string
+}
+
+func (m *fakeMetrics) IncOp(op, table string, success bool) {
+	m.ops = append(m.ops, fmt.Sprintf("%s %s %v", op, table, success))
+}
+
+func (m *fakeMetrics) ObserveLatency(op, table string, d time.Duration) {
+	m.latencies = append(m.latencies, op+" "+table)
+}
+
+func TestMetrics(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	metrics := &fakeMetrics{}
+	repo.SetMetrics(metrics)
+	ctx := context.Background()
+
+	var user User
+
+	if _, err := repo.Create(ctx, &User{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = repo.FirstOrFail(ctx, &user, "name = ?", "bob")
+
+	if !reflect.DeepEqual(metrics.ops, []string{"Create users true", "FirstOrFail users false"}) {
+		t.Fatalf("got %v, want one increment per call", metrics.ops)
+	}
+
+	if !reflect.DeepEqual(metrics.latencies, []string{"Create users", "FirstOrFail users"}) {
+		t.Fatalf("got %v, want one latency per call", metrics.latencies)
+	}
+}
+
+// delayKey is the context key of the delay added to the queries of a delayed database
+type delayKey struct{}
+
+// delayed makes the queries of db sleep for the delay found in their context
+func delayed(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	err := db.Callback().Query().Before("gorm:query").Register("test:delay", func(tx *gorm.DB) {
+		if delay, ok := tx.Statement.Context.Value(delayKey{}).(time.Duration); ok {
+			time.Sleep(delay)
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlowQuery(t *testing.T) {
+	db := openDB(t)
+	delayed(t, db)
+	repo := InitRepository[User](db)
+
+	var slow []string
+
+	repo.SetSlowThreshold(20 * time.Millisecond)
+	repo.OnSlowQuery(func(op string, d time.Duration) {
+		if d <= 20*time.Millisecond {
+			t.Errorf("got %v, want a duration above the threshold", d)
+		}
+
+		slow = append(slow, op)
+	})
+
+	var users []User
+
+	if err := repo.Find(context.Background(), &users); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Find(context.WithValue(context.Background(), delayKey{}, 40*time.Millisecond), &users); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(slow, []string{"Find"}) {
+		t.Fatalf("got %v, want only the delayed query reported", slow)
+	}
+}
+
+type AuditEntry struct {
+	ID     uint
+	Action string
+}
+
+func (AuditEntry) TableName() string { return "audit_entries" }
+
+// Audited writes an audit entry with another repository when it's created
+type Audited struct {
+	ID    uint
+	Name  string
+	audit IRepository[AuditEntry]
+}
+
+func (Audited) TableName() string { return "audited" }
+
+func (a *Audited) AfterRepositoryCreate(ctx context.Context) error {
+	var previous AuditEntry
+
+	if err := a.audit.Last(ctx, &previous); err != nil {
+		return err
+	}
+
+	_, err := a.audit.Create(ctx, &AuditEntry{Action: "create " + a.Name})
+
+	return err
+}
+
+func TestLoggerOtherRepository(t *testing.T) {
+	db := openDB(t, &Audited{}, &AuditEntry{})
+	repo := InitRepository[Audited](db)
+	audit := InitRepository[AuditEntry](db)
+	logger, auditLogger := &fakeLogger{}, &fakeLogger{}
+	repo.SetLogger(logger)
+	audit.SetLogger(auditLogger)
+
+	if _, err := repo.Create(context.Background(), &Audited{Name: "alice", audit: audit}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ops := logger.observed(); !reflect.DeepEqual(ops, []string{"Create audited"}) {
+		t.Fatalf("got %v", ops)
+	}
+
+	// the operations of the hook belong to the audit repository, not to the Create running the hook
+	if ops := auditLogger.observed(); !reflect.DeepEqual(ops, []string{"Last audit_entries", "Create audit_entries"}) {
+		t.Fatalf("got %v, want the operations of the hook observed by their repository", ops)
+	}
+}
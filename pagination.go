@@ -0,0 +1,164 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultPage is used when a page lower than 1 is requested
+	DefaultPage = 1
+	// DefaultPageSize is used when a page size lower than 1 is requested
+	DefaultPageSize = 10
+)
+
+// Paginate finds the records of the given page, matching given conditions.
+// page starts from 1, page < 1 falls back to DefaultPage and pageSize <= 0 falls back to DefaultPageSize.
+func (r *Repository[T]) Paginate(ctx context.Context, models *[]T, page, pageSize int, conds ...interface{}) error {
+	page, pageSize = normalizePage(page, pageSize)
+
+	return r.run(ctx, "Paginate", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Offset((page - 1) * pageSize).Limit(pageSize).Find(models)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, models)
+	})
+}
+
+// Page holds a single page of records alongside pagination metadata
+type Page[T IBaseModel] struct {
+	Items      []T   `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// FindPaginated finds the records of the given page matching given conditions
+// and counts all the matching records, both in a single transaction.
+// page and pageSize are normalized the same as Paginate.
+func (r *Repository[T]) FindPaginated(ctx context.Context, page, pageSize int, conds ...interface{}) (*Page[T], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	result := &Page[T]{
+		Items:    []T{},
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	err := r.run(ctx, "FindPaginated", func(ctx context.Context) (int64, error) {
+		var rows int64
+		err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+			if res := where(r.readScoped(tx).Model(new(T)), conds).Count(&result.Total); res.Error != nil {
+				return res.Error
+			}
+
+			if result.Total == 0 {
+				return nil
+			}
+
+			res := where(r.readScoped(tx).Model(new(T)), conds).Offset((page - 1) * pageSize).Limit(pageSize).Find(&result.Items)
+			rows = res.RowsAffected
+
+			if res.Error != nil {
+				return res.Error
+			}
+
+			return r.decrypt(ctx, &result.Items)
+		})
+
+		return rows, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalPages = int((result.Total + int64(pageSize) - 1) / int64(pageSize))
+
+	return result, nil
+}
+
+// FindAndCount finds the records of the given page matching given conditions into models and returns
+// the count of all the matching records, both in a single transaction like FindPaginated.
+// page and pageSize are normalized the same as Paginate.
+func (r *Repository[T]) FindAndCount(ctx context.Context, models *[]T, page, pageSize int, conds ...interface{}) (int64, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	var total int64
+	err := r.run(ctx, "FindAndCount", func(ctx context.Context) (int64, error) {
+		var rows int64
+		err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+			if res := where(r.readScoped(tx).Model(new(T)), conds).Count(&total); res.Error != nil {
+				return res.Error
+			}
+
+			if total == 0 {
+				*models = []T{}
+				return nil
+			}
+
+			res := where(r.readScoped(tx).Model(new(T)), conds).Offset((page - 1) * pageSize).Limit(pageSize).Find(models)
+			rows = res.RowsAffected
+
+			if res.Error != nil {
+				return res.Error
+			}
+
+			return r.decrypt(ctx, models)
+		})
+
+		return rows, err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// FindAfter finds up to limit records whose cursorColumn is greater than cursorValue,
+// ordered ascending by cursorColumn and matching given conditions.
+// Pass the cursorColumn value of the last record to fetch the next chunk, cursorColumn
+// is validated against the model schema and limit <= 0 falls back to DefaultPageSize.
+func (r *Repository[T]) FindAfter(ctx context.Context, models *[]T, cursorColumn string, cursorValue interface{}, limit int, conds ...interface{}) error {
+	_, limit = normalizePage(DefaultPage, limit)
+
+	return r.run(ctx, "FindAfter", func(ctx context.Context) (int64, error) {
+		column, err := r.column(cursorColumn)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.query(ctx, conds).
+			Where(clause.Gt{Column: clause.Column{Name: column}, Value: cursorValue}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: column}}).
+			Limit(limit).
+			Find(models)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, models)
+	})
+}
+
+// normalizePage clamps page and pageSize to sane values
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = DefaultPage
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return page, pageSize
+}
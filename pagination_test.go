@@ -0,0 +1,154 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// seedN inserts n users named user01, user02...
+func seedN(t *testing.T, repo IRepository[User], n int) {
+	t.Helper()
+
+	names := make([]string, n)
+
+	for i := range names {
+		names[i] = fmt.Sprintf("user%02d", i+1)
+	}
+
+	seed(t, repo, names...)
+}
+
+func TestPaginate(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seedN(t, repo, 25)
+
+	var users []User
+
+	if err := repo.Paginate(ctx, &users, 2, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 10 || users[0].ID != 11 || users[9].ID != 20 {
+		t.Fatalf("got %d users from %d, want rows 11-20", len(users), users[0].ID)
+	}
+
+	if err := repo.Paginate(ctx, &users, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != DefaultPageSize || users[0].ID != 1 {
+		t.Fatalf("got %d users from %d, want the first default page", len(users), users[0].ID)
+	}
+}
+
+func TestFindPaginated(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+
+	page, err := repo.FindPaginated(ctx, 1, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Total != 0 || page.TotalPages != 0 || len(page.Items) != 0 {
+		t.Fatalf("got %+v, want an empty page", page)
+	}
+
+	seedN(t, repo, 25)
+	page, err = repo.FindPaginated(ctx, 3, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Total != 25 || page.TotalPages != 3 || page.Page != 3 || page.PageSize != 10 || len(page.Items) != 5 {
+		t.Fatalf("got %+v, want a partial last page", page)
+	}
+
+	page, err = repo.FindPaginated(ctx, 1, 10, "id <= ?", 12)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Total != 12 || page.TotalPages != 2 || len(page.Items) != 10 {
+		t.Fatalf("got %+v, want the conditions applied to the count and the page", page)
+	}
+}
+
+func TestFindAndCount(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seedN(t, repo, 25)
+	rec := record(t, db)
+
+	var users []User
+
+	if total, err := repo.FindAndCount(ctx, &users, 1, 10); err != nil || total != 25 || len(users) != 10 || users[0].ID != 1 {
+		t.Fatalf("got %d, %d users, %v, want total 25 and the first 10 rows", total, len(users), err)
+	}
+
+	if n := rec.executed("SELECT"); n != 2 {
+		t.Fatalf("got %d queries, want the count and the page", n)
+	}
+
+	if total, err := repo.FindAndCount(ctx, &users, 3, 10, "id > ?", 5); err != nil || total != 20 || len(users) != 0 {
+		t.Fatalf("got %d, %d users, %v, want total 20 past the last page", total, len(users), err)
+	}
+
+	rec.reset()
+
+	if total, err := repo.FindAndCount(ctx, &users, 1, 10, "id > ?", 25); err != nil || total != 0 || users == nil || len(users) != 0 {
+		t.Fatalf("got %d, %v, %v, want an empty page", total, users, err)
+	}
+
+	if n := rec.executed("SELECT"); n != 1 {
+		t.Fatalf("got %d queries, want the page skipped when nothing matches", n)
+	}
+}
+
+func TestFindAfter(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seedN(t, repo, 23)
+
+	seen := map[uint]bool{}
+	var cursor uint
+
+	for {
+		var users []User
+
+		if err := repo.FindAfter(ctx, &users, "id", cursor, 5); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(users) == 0 {
+			break
+		}
+
+		for _, user := range users {
+			if seen[user.ID] {
+				t.Fatalf("user %d found twice", user.ID)
+			}
+
+			seen[user.ID] = true
+		}
+
+		cursor = users[len(users)-1].ID
+	}
+
+	if len(seen) != 23 {
+		t.Fatalf("got %d users, want 23", len(seen))
+	}
+
+	var users []User
+
+	if err := repo.FindAfter(ctx, &users, "id; DROP TABLE users", 0, 5); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
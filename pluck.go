@@ -0,0 +1,66 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+)
+
+// Distinct scans the distinct values of column over the records matching given conditions
+// into dest, which should be a pointer to a slice. column is validated against the model schema.
+//
+//	var countries []string
+//	repo.Distinct(ctx, "country", &countries)
+func (r *Repository[T]) Distinct(ctx context.Context, column string, dest interface{}, conds ...interface{}) error {
+	return r.run(ctx, "Distinct", func(ctx context.Context) (int64, error) {
+		name, err := r.column(column)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.query(ctx, conds).
+			Clauses(clause.Select{Distinct: true, Columns: []clause.Column{{Table: clause.CurrentTable, Name: name}}}).
+			Scan(dest)
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// Pluck scans column of the records matching given conditions into dest,
+// which should be a pointer to a slice of the column type. column is validated against the model schema.
+//
+//	var ids []uint
+//	repo.Pluck(ctx, "id", &ids, "status = ?", "active")
+func (r *Repository[T]) Pluck(ctx context.Context, column string, dest interface{}, conds ...interface{}) error {
+	return r.run(ctx, "Pluck", func(ctx context.Context) (int64, error) {
+		name, err := r.column(column)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.query(ctx, conds).Pluck(name, dest)
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// PluckDistinct scans the distinct values of column of the records matching given conditions into dest like Pluck,
+// which should be a pointer to a slice of the column type. column is validated against the model schema.
+//
+//	var statuses []string
+//	repo.PluckDistinct(ctx, "status", &statuses)
+func (r *Repository[T]) PluckDistinct(ctx context.Context, column string, dest interface{}, conds ...interface{}) error {
+	return r.run(ctx, "PluckDistinct", func(ctx context.Context) (int64, error) {
+		name, err := r.column(column)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.query(ctx, conds).Distinct().Pluck(name, dest)
+
+		return res.RowsAffected, res.Error
+	})
+}
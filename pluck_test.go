@@ -0,0 +1,96 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+)
+
+type Address struct {
+	ID      uint
+	Country string
+}
+
+func (Address) TableName() string { return "addresses" }
+
+func TestDistinct(t *testing.T) {
+	repo := InitRepository[Address](openDB(t, &Address{}))
+	ctx := context.Background()
+	addresses := []*Address{{Country: "NL"}, {Country: "DE"}, {Country: "NL"}, {Country: "FR"}, {Country: "DE"}}
+
+	if _, err := repo.BatchCreate(ctx, addresses); err != nil {
+		t.Fatal(err)
+	}
+
+	var countries []string
+
+	if err := repo.Distinct(ctx, "country", &countries); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(countries)
+
+	if len(countries) != 3 || countries[0] != "DE" || countries[1] != "FR" || countries[2] != "NL" {
+		t.Fatalf("got %v, want each country once", countries)
+	}
+
+	if err := repo.Distinct(ctx, "missing", &countries); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestPluck(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol")
+
+	var ids []uint
+
+	if err := repo.Pluck(ctx, "id", &ids, "name <> ?", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("got %v, want [1 3]", ids)
+	}
+
+	if err := repo.Pluck(ctx, "missing", &ids); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestPluckDistinct(t *testing.T) {
+	repo := InitRepository[Purchase](openDB(t, &Purchase{}))
+	ctx := context.Background()
+	statuses := []string{"paid", "pending", "paid", "shipped", "paid", "pending", "shipped", "paid"}
+	purchases := make([]*Purchase, 0, len(statuses))
+
+	for _, status := range statuses {
+		purchases = append(purchases, &Purchase{CustomerID: 1, Status: status})
+	}
+
+	if _, err := repo.BatchCreate(ctx, purchases); err != nil {
+		t.Fatal(err)
+	}
+
+	var distinct []string
+
+	if err := repo.PluckDistinct(ctx, "status", &distinct); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(distinct)
+
+	if len(distinct) != 3 || distinct[0] != "paid" || distinct[1] != "pending" || distinct[2] != "shipped" {
+		t.Fatalf("got %v, want each status once", distinct)
+	}
+
+	if err := repo.PluckDistinct(ctx, "status", &distinct, "status <> ?", "paid"); err != nil || len(distinct) != 2 {
+		t.Fatalf("got %v, %v, want pending and shipped", distinct, err)
+	}
+
+	if err := repo.PluckDistinct(ctx, "status; DROP TABLE purchases", &distinct); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
@@ -0,0 +1,227 @@
+package regorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QueryOption shapes the query of a read method, options can be passed
+// along with the conditions of read methods in any order:
+//
+//	repo.Find(ctx, &users, regorm.Preload("Orders"), "age > ?", 18)
+//	repo.Find(ctx, &users, regorm.Where("age > ?", 18), regorm.OrderBy("name", false), regorm.Limit(10))
+//
+// Any func(*gorm.DB) *gorm.DB, like a GORM scope, is accepted as a query option too.
+type QueryOption func(db *gorm.DB) *gorm.DB
+
+// Where adds a condition to the query, multiple Where options are joined with AND
+func Where(query interface{}, args ...interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// Limit limits the number of records the query returns
+func Limit(limit int) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Limit(limit)
+	}
+}
+
+// Offset skips the given number of records before returning the records
+func Offset(offset int) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(offset)
+	}
+}
+
+// WhereIn matches the records whose column is in the result of subquery, column is validated
+// against the model schema and ErrInvalidColumn is returned by the read method if it doesn't exist.
+// The subquery can be built from another repository with SubQuery:
+//
+//	paid := orderRepo.SubQuery(regorm.Select("user_id"), regorm.Where("status = ?", "paid"))
+//	userRepo.Find(ctx, &users, regorm.WhereIn("id", paid))
+func WhereIn(column string, subquery *gorm.DB) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		name, err := parseColumn(db, db.Statement.Model, column)
+
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		return db.Where("? IN (?)", clause.Column{Table: clause.CurrentTable, Name: name}, subquery)
+	}
+}
+
+// Join joins the query with another table, the selected columns still belong to the model
+//
+//	repo.Find(ctx, &users, regorm.Join("JOIN orders ON orders.user_id = users.id AND orders.status = ?", "paid"))
+func Join(query string, args ...interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins(query, args...)
+	}
+}
+
+// Preload eager loads the given association, conds are applied to the association query
+func Preload(association string, conds ...interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload(association, conds...)
+	}
+}
+
+// PreloadNested eager loads a nested association path like "Orders.Items", each level
+// of the path is loaded with a single query and conds are applied to the last one
+func PreloadNested(path string, conds ...interface{}) QueryOption {
+	return Preload(strings.Trim(path, "."), conds...)
+}
+
+// PreloadWhere eager loads only the records of the association matching the query
+//
+//	repo.Find(ctx, &users, regorm.PreloadWhere("Orders", "status = ?", "active"))
+func PreloadWhere(association string, query interface{}, args ...interface{}) QueryOption {
+	return Preload(association, append([]interface{}{query}, args...)...)
+}
+
+// PreloadFunc eager loads the association using fn to shape the association query
+func PreloadFunc(association string, fn func(*gorm.DB) *gorm.DB) QueryOption {
+	return Preload(association, fn)
+}
+
+// Select selects only the given columns, the other fields are left zero valued
+func Select(columns ...string) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Select(columns)
+	}
+}
+
+// Omit excludes the given columns from the query, see CreateOmit and UpdateOmit for writes
+func Omit(columns ...string) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Omit(columns...)
+	}
+}
+
+// Order orders the query by a raw order clause like "created_at DESC",
+// the clause is not escaped so never build it from user input, use OrderBy instead
+func Order(clause string) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause)
+	}
+}
+
+// OrderBy orders the query by column, column is validated against the model schema
+// and ErrInvalidColumn is returned by the read method if it doesn't exist
+func OrderBy(column string, desc bool) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		name, err := parseColumn(db, db.Statement.Model, column)
+
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		return db.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: name}, Desc: desc})
+	}
+}
+
+// ApplySort orders the query by a user supplied sort spec like "name,-created_at", fields are
+// comma separated and prefixed with "-" to sort descending. Fields are mapped to columns through
+// allowed and the fields which aren't allowed are ignored, so the spec can come from a request:
+//
+//	repo.Find(ctx, &users, regorm.ApplySort(r.URL.Query().Get("sort"), map[string]string{
+//		"name":       "name",
+//		"created_at": "created_at",
+//	}))
+func ApplySort(sortSpec string, allowed map[string]string) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, field := range strings.Split(sortSpec, ",") {
+			field = strings.TrimSpace(field)
+			desc := strings.HasPrefix(field, "-")
+			column, ok := allowed[strings.TrimPrefix(field, "-")]
+
+			if !ok || column == "" {
+				continue
+			}
+
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+		}
+
+		return db
+	}
+}
+
+// where applies the query options found in conds, the remaining conds are applied
+// as inline conditions the same way GORM's finisher methods (First, Find, ...) do
+func where(db *gorm.DB, conds []interface{}) *gorm.DB {
+	inline := make([]interface{}, 0, len(conds))
+
+	for _, cond := range conds {
+		switch option := cond.(type) {
+		case QueryOption:
+			db = option(db)
+		case func(*gorm.DB) *gorm.DB:
+			db = option(db)
+		case Specification:
+			db = option.Apply(db)
+		default:
+			inline = append(inline, cond)
+		}
+	}
+
+	if len(inline) == 0 {
+		return db
+	}
+
+	return db.Where(inline[0], inline[1:]...)
+}
+
+// emptyCondition reports whether conds is empty, so it would match all the records
+func emptyCondition(conds interface{}) bool {
+	if conds == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(conds)
+
+	switch value.Kind() {
+	case reflect.String, reflect.Map, reflect.Slice:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil() || emptyCondition(value.Elem().Interface())
+	case reflect.Struct:
+		return value.IsZero()
+	}
+
+	return false
+}
+
+// toSlice converts a slice of any type to []interface{}, other values are wrapped in a slice.
+// Arrays and slices storing a single database value like uuid.UUID or []byte are values, not slices.
+func toSlice(values interface{}) []interface{} {
+	value := reflect.ValueOf(values)
+
+	if _, ok := values.(driver.Valuer); ok || value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+		return []interface{}{values}
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		if values == nil {
+			return nil
+		}
+
+		return []interface{}{values}
+	}
+
+	result := make([]interface{}, value.Len())
+
+	for i := range result {
+		result[i] = value.Index(i).Interface()
+	}
+
+	return result
+}
@@ -0,0 +1,319 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// seedCustomers inserts two customers, alice with an active and a cancelled order of two items each, bob with none
+func seedCustomers(t *testing.T, repo IRepository[Customer]) {
+	t.Helper()
+
+	customers := []*Customer{
+		{Name: "alice", Orders: []Purchase{
+			{Status: "active", Items: []Item{{Name: "a1"}, {Name: "a2"}}},
+			{Status: "cancelled", Items: []Item{{Name: "c1"}, {Name: "c2"}}},
+		}},
+		{Name: "bob"},
+	}
+
+	if _, err := repo.BatchCreate(context.Background(), customers); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPreload(t *testing.T) {
+	repo := InitRepository[Customer](openDB(t, &Customer{}, &Purchase{}, &Item{}))
+	seedCustomers(t, repo)
+
+	var customers []Customer
+
+	if err := repo.Find(context.Background(), &customers, Preload("Orders")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(customers) != 2 || len(customers[0].Orders) != 2 || len(customers[1].Orders) != 0 {
+		t.Fatalf("got %+v, want the orders loaded", customers)
+	}
+}
+
+func TestPreloadNested(t *testing.T) {
+	db := openDB(t, &Customer{}, &Purchase{}, &Item{})
+	repo := InitRepository[Customer](db)
+	seedCustomers(t, repo)
+	rec := record(t, db)
+
+	var customers []Customer
+
+	if err := repo.Find(context.Background(), &customers, PreloadNested("Orders.Items")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(customers[0].Orders) != 2 || len(customers[0].Orders[0].Items) != 2 || len(customers[0].Orders[1].Items) != 2 {
+		t.Fatalf("got %+v, want the orders and their items loaded", customers)
+	}
+
+	if n := rec.count("SELECT"); n != 3 {
+		t.Fatalf("got %d queries, want 3", n)
+	}
+}
+
+func TestPreloadWhere(t *testing.T) {
+	repo := InitRepository[Customer](openDB(t, &Customer{}, &Purchase{}, &Item{}))
+	ctx := context.Background()
+	seedCustomers(t, repo)
+
+	var customer Customer
+
+	if err := repo.First(ctx, &customer, PreloadWhere("Orders", "status = ?", "active")); err != nil {
+		t.Fatal(err)
+	}
+
+	if customer.Name != "alice" || len(customer.Orders) != 1 || customer.Orders[0].Status != "active" {
+		t.Fatalf("got %+v, want only the active order", customer)
+	}
+
+	customer = Customer{}
+	active := func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", "active")
+	}
+
+	if err := repo.First(ctx, &customer, PreloadFunc("Orders", active)); err != nil {
+		t.Fatal(err)
+	}
+
+	if customer.Name != "alice" || len(customer.Orders) != 1 || customer.Orders[0].Status != "active" {
+		t.Fatalf("got %+v, want only the active order", customer)
+	}
+
+	customer = Customer{}
+
+	if err := repo.First(ctx, &customer, PreloadWhere("Orders", "status = ?", "refunded")); err != nil {
+		t.Fatal(err)
+	}
+
+	if customer.Name != "alice" || len(customer.Orders) != 0 {
+		t.Fatalf("got %+v, want the customer without orders", customer)
+	}
+}
+
+type Article struct {
+	ID          uint
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+func (Article) TableName() string { return "articles" }
+
+func TestSelect(t *testing.T) {
+	repo := InitRepository[Article](openDB(t, &Article{}))
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &Article{Name: "a", Description: strings.Repeat("x", 4096)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var articles []Article
+
+	if err := repo.Find(ctx, &articles, Select("id", "name")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 1 || articles[0].ID == 0 || articles[0].Name != "a" || articles[0].Description != "" {
+		t.Fatalf("got %+v, want only id and name", articles)
+	}
+}
+
+func TestOmit(t *testing.T) {
+	repo := InitRepository[Article](openDB(t, &Article{}))
+	ctx := context.Background()
+
+	article := &Article{Name: "a", Description: "kept"}
+
+	if _, err := repo.CreateOmit(ctx, article, "description"); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored Article
+
+	if err := repo.First(ctx, &stored, article.ID); err != nil || stored.Description != "" {
+		t.Fatalf("got %+v, %v, want description not inserted", stored, err)
+	}
+
+	article = &Article{Name: "a", Description: "stored"}
+
+	if _, err := repo.Create(ctx, article); err != nil {
+		t.Fatal(err)
+	}
+
+	article.Name = "b"
+	article.Description = "changed"
+
+	if err := repo.UpdateOmit(ctx, article, "description"); err != nil {
+		t.Fatal(err)
+	}
+
+	stored = Article{}
+
+	if err := repo.First(ctx, &stored, article.ID); err != nil || stored.Name != "b" || stored.Description != "stored" {
+		t.Fatalf("got %+v, %v, want description unchanged", stored, err)
+	}
+
+	var articles []Article
+
+	if err := repo.Find(ctx, &articles, Omit("description")); err != nil || len(articles) != 2 || articles[1].Description != "" {
+		t.Fatalf("got %+v, %v, want description omitted on read", articles, err)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	repo := InitRepository[Article](openDB(t, &Article{}))
+	ctx := context.Background()
+	now := time.Now()
+
+	seeded := []*Article{
+		{Name: "old", CreatedAt: now.Add(-time.Hour)},
+		{Name: "new", CreatedAt: now},
+		{Name: "middle", CreatedAt: now.Add(-time.Minute)},
+	}
+
+	if _, err := repo.BatchCreate(ctx, seeded); err != nil {
+		t.Fatal(err)
+	}
+
+	var articles []Article
+
+	if err := repo.Find(ctx, &articles, Order("created_at DESC")); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := []string{articles[0].Name, articles[1].Name, articles[2].Name}; !reflect.DeepEqual(names, []string{"new", "middle", "old"}) {
+		t.Fatalf("got %v, want ordered by created_at descending", names)
+	}
+
+	if err := repo.Find(ctx, &articles, OrderBy("created_at", false)); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := []string{articles[0].Name, articles[1].Name, articles[2].Name}; !reflect.DeepEqual(names, []string{"old", "middle", "new"}) {
+		t.Fatalf("got %v, want ordered by created_at ascending", names)
+	}
+
+	if err := repo.Find(ctx, &articles, OrderBy("created_at; DROP TABLE articles", true)); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestApplySort(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seed(t, repo, "bob", "alice", "bob")
+
+	allowed := map[string]string{"name": "name", "joined": "id"}
+	rec := record(t, db)
+
+	var users []User
+
+	if err := repo.Find(ctx, &users, ApplySort("name, -joined,password,-age; DROP TABLE users", allowed)); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); !strings.HasSuffix(sql, "ORDER BY `name`,`id` DESC") {
+		t.Fatalf("got %q, want the disallowed fields dropped", sql)
+	}
+
+	if ids := []uint{users[0].ID, users[1].ID, users[2].ID}; !reflect.DeepEqual(ids, []uint{2, 3, 1}) {
+		t.Fatalf("got %v, want ordered by name then id descending", ids)
+	}
+
+	if err := repo.Find(ctx, &users, ApplySort("age", allowed)); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); strings.Contains(sql, "ORDER BY") {
+		t.Fatalf("got %q, want no ordering", sql)
+	}
+}
+
+func TestQueryOptions(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol", "dave")
+	rec := record(t, db)
+
+	var users []User
+
+	if err := repo.Find(ctx, &users, Where("age > ?", 20), Order("age DESC"), Limit(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 2 || users[0].Name != "dave" || users[1].Name != "carol" {
+		t.Fatalf("got %+v, want dave and carol", users)
+	}
+
+	if sql := rec.last(); !strings.Contains(sql, "WHERE age > ?") || !strings.Contains(sql, "ORDER BY age DESC") || !strings.Contains(sql, "LIMIT 2") {
+		t.Fatalf("got %q, want the options applied", sql)
+	}
+
+	if err := repo.Find(ctx, &users, Order("age"), Offset(1), Limit(1)); err != nil || len(users) != 1 || users[0].Name != "bob" {
+		t.Fatalf("got %+v, %v, want bob", users, err)
+	}
+
+	if n, err := repo.Count(ctx, Where("age > ?", 21)); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2", n, err)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, Where("name = ?", "carol"), Select("id", "name")); err != nil || user.Name != "carol" || user.Email != "" {
+		t.Fatalf("got %+v, %v, want only id and name of carol", user, err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	repo := InitRepository[Customer](openDB(t, &Customer{}, &Purchase{}, &Item{}))
+	seedCustomers(t, repo)
+
+	var customers []Customer
+	err := repo.Find(context.Background(), &customers,
+		Join("JOIN purchases ON purchases.customer_id = customers.id AND purchases.status = ?", "cancelled"))
+
+	if err != nil || len(customers) != 1 || customers[0].Name != "alice" {
+		t.Fatalf("got %+v, %v, want alice", customers, err)
+	}
+}
+
+func TestWhereIn(t *testing.T) {
+	db := openDB(t, &Customer{}, &Purchase{}, &Item{})
+	customers := InitRepository[Customer](db)
+	purchases := InitRepository[Purchase](db)
+	seedCustomers(t, customers)
+
+	if _, err := customers.Create(context.Background(), &Customer{Name: "carol", Orders: []Purchase{{Status: "active"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var found []Customer
+	active := purchases.SubQuery(Select("customer_id"), Where("status = ?", "active"))
+
+	if err := customers.Find(context.Background(), &found, WhereIn("id", active)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(found) != 2 || found[0].Name != "alice" || found[1].Name != "carol" {
+		t.Fatalf("got %+v, want alice and carol", found)
+	}
+
+	if err := customers.Find(context.Background(), &found, WhereIn("missing", active)); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
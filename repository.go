@@ -1,7 +1,13 @@
 package regorm
 
 import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // IRepository a generic interface for repositories
@@ -12,15 +18,117 @@ import (
 //		data.IRepository[SampleModel]
 //	}
 type IRepository[T IBaseModel] interface {
-	First(model *T, conds ...interface{}) error        // Select query with limit 1
-	FirstOrFail(model *T, conds ...interface{}) error  // Select query with limit 1 and return error if finds nothing
-	Find(model *[]T, conds ...interface{}) error       // Select query
-	FindOrFail(model *[]T, conds ...interface{}) error // Select query and return error if finds nothing
-	Create(model *T) (*T, error)                       // Insert model
-	BatchCreate(models []*T) (int64, error)            // Batch Insert based on slice of model
-	Update(model *T) error                             // Update a model
-	Delete(model *T) (int64, error)                    // Delete a record
-	GetDB() *gorm.DB                                   // Get Database Instance
+	First(ctx context.Context, model *T, conds ...interface{}) error                                                                 // Select query with limit 1
+	FirstOrFail(ctx context.Context, model *T, conds ...interface{}) error                                                           // Select query with limit 1 and return error if finds nothing
+	FirstForUpdate(ctx context.Context, model *T, conds ...interface{}) error                                                        // Select query with limit 1 locking the record for update
+	FirstForShare(ctx context.Context, model *T, conds ...interface{}) error                                                         // Select query with limit 1 locking the record in share mode
+	Last(ctx context.Context, model *T, conds ...interface{}) error                                                                  // Select query ordered descending with limit 1
+	LastOrFail(ctx context.Context, model *T, conds ...interface{}) error                                                            // Select query ordered descending with limit 1 and return error if finds nothing
+	Take(ctx context.Context, model *T, conds ...interface{}) error                                                                  // Select query with limit 1 without ordering
+	TakeOrFail(ctx context.Context, model *T, conds ...interface{}) error                                                            // Select query with limit 1 without ordering and return error if finds nothing
+	Find(ctx context.Context, model *[]T, conds ...interface{}) error                                                                // Select query
+	FindOrFail(ctx context.Context, model *[]T, conds ...interface{}) error                                                          // Select query and return error if finds nothing
+	FindByID(ctx context.Context, model *T, id interface{}) error                                                                    // Select query by primary key
+	FindByIDOrFail(ctx context.Context, model *T, id interface{}) error                                                              // Select query by primary key and return error if finds nothing
+	FindByIDs(ctx context.Context, models *[]T, ids interface{}) error                                                               // Select query by a slice of primary keys
+	FindByCompositeKey(ctx context.Context, model *T, keys map[string]interface{}) error                                             // Find the record whose primary key columns equal keys
+	FirstBy(ctx context.Context, model *T, column string, value interface{}) error                                                   // Select query with limit 1 where column equals value
+	FindBy(ctx context.Context, models *[]T, column string, value interface{}) error                                                 // Select query where column equals value
+	FindByFilters(ctx context.Context, models *[]T, filters map[string]interface{}) error                                            // Select query where columns equal the values of filters, unknown columns are ignored
+	FirstWithTrashed(ctx context.Context, model *T, conds ...interface{}) error                                                      // Select query with limit 1 including soft deleted records
+	FindWithTrashed(ctx context.Context, models *[]T, conds ...interface{}) error                                                    // Select query including soft deleted records
+	CountWithTrashed(ctx context.Context, conds ...interface{}) (int64, error)                                                       // Count records matching conditions including soft deleted records
+	FirstOnlyTrashed(ctx context.Context, model *T, conds ...interface{}) error                                                      // Select query with limit 1 among soft deleted records
+	FindOnlyTrashed(ctx context.Context, models *[]T, conds ...interface{}) error                                                    // Select query among soft deleted records
+	Create(ctx context.Context, model *T) (*T, error)                                                                                // Insert model
+	FirstOrCreate(ctx context.Context, model *T, conds ...interface{}) (*T, bool, error)                                             // Select query with limit 1 and insert model if finds nothing
+	FirstOrInit(ctx context.Context, model *T, conds ...interface{}) error                                                           // Select query with limit 1 and initialize model with the conditions if finds nothing
+	BatchCreate(ctx context.Context, models []*T) (int64, error)                                                                     // Batch Insert based on slice of model
+	BatchCreateInChunks(ctx context.Context, models []*T, chunkSize int) (int64, error)                                              // Batch Insert based on slice of model chunkSize models per statement in a transaction
+	CreateIgnore(ctx context.Context, models []*T) (int64, error)                                                                    // Batch Insert skipping the models conflicting with existing records
+	CreateOmit(ctx context.Context, model *T, omit ...string) (*T, error)                                                            // Insert model without the omitted columns
+	CreateReturning(ctx context.Context, model *T, columns ...string) (*T, error)                                                    // Insert value and read back the given database generated columns
+	Upsert(ctx context.Context, model *T, conflictColumns []string, updateColumns []string) error                                    // Insert model or update columns on conflict
+	UpsertReturning(ctx context.Context, model *T, conflictColumns []string, updateColumns []string) (bool, error)                   // Upsert model and report whether it was inserted
+	BatchUpsert(ctx context.Context, models []*T, conflictColumns []string, updateColumns []string) (int64, error)                   // Insert values in one statement, updating the records they conflict with
+	Update(ctx context.Context, model *T) error                                                                                      // Update a model
+	UpdateOmit(ctx context.Context, model *T, omit ...string) error                                                                  // Update a model without the omitted columns
+	UpdateExisting(ctx context.Context, model *T) (int64, error)                                                                     // Update a model and return ErrNotFound instead of inserting it if it doesn't exist
+	UpdateColumns(ctx context.Context, conds interface{}, values map[string]interface{}) (int64, error)                              // Update only the given columns of matching records
+	UpdateWhere(ctx context.Context, conds interface{}, values map[string]interface{}) (int64, error)                                // Bulk update records matching a non empty condition
+	Increment(ctx context.Context, conds interface{}, column string, delta int64) (int64, error)                                     // Atomically add delta to a numeric column
+	Decrement(ctx context.Context, conds interface{}, column string, delta int64) (int64, error)                                     // Atomically subtract delta from a numeric column
+	Delete(ctx context.Context, model *T) (int64, error)                                                                             // Delete a record
+	DeleteByID(ctx context.Context, id interface{}) (int64, error)                                                                   // Delete a record by primary key
+	ForceDelete(ctx context.Context, model *T) (int64, error)                                                                        // Permanently delete a record even if it's soft deletable
+	Restore(ctx context.Context, model *T) (int64, error)                                                                            // Restore a soft deleted record
+	DeleteWhere(ctx context.Context, conds interface{}) (int64, error)                                                               // Bulk delete records matching a non empty condition
+	DeleteInChunks(ctx context.Context, conds interface{}, chunkSize int) (int64, error)                                             // Bulk delete records matching a non empty condition chunkSize records at a time
+	Count(ctx context.Context, conds ...interface{}) (int64, error)                                                                  // Count records matching conditions
+	Exists(ctx context.Context, conds ...interface{}) (bool, error)                                                                  // Check if any record matches conditions
+	ExistsBy(ctx context.Context, column string, value interface{}) (bool, error)                                                    // Check if any record has column equal to value
+	Sum(ctx context.Context, column string, conds ...interface{}) (float64, error)                                                   // Sum of column over matching records
+	Avg(ctx context.Context, column string, conds ...interface{}) (float64, error)                                                   // Average of column over matching records
+	Min(ctx context.Context, column string, conds ...interface{}) (float64, error)                                                   // Minimum of column over matching records
+	Max(ctx context.Context, column string, conds ...interface{}) (float64, error)                                                   // Maximum of column over matching records
+	GroupCount(ctx context.Context, groupColumn string, conds ...interface{}) (map[string]int64, error)                              // Count matching records per value of a column
+	Distinct(ctx context.Context, column string, dest interface{}, conds ...interface{}) error                                       // Select distinct values of a column
+	Pluck(ctx context.Context, column string, dest interface{}, conds ...interface{}) error                                          // Select a single column into a slice
+	PluckDistinct(ctx context.Context, column string, dest interface{}, conds ...interface{}) error                                  // Select the distinct values of a single column into a slice
+	ScanInto(ctx context.Context, dest interface{}, opts ...QueryOption) error                                                       // Scan a query over the model into an arbitrary destination
+	FindMaps(ctx context.Context, dest *[]map[string]interface{}, opts ...QueryOption) error                                         // Scan a query over the model into a map per record
+	Raw(ctx context.Context, dest interface{}, sql string, args ...interface{}) error                                                // Run a raw SQL query and scan the result
+	Exec(ctx context.Context, sql string, args ...interface{}) (int64, error)                                                        // Run a raw SQL statement
+	FindInBatches(ctx context.Context, batchSize int, fn func(batch []T) error, conds ...interface{}) error                          // Process matching records batch by batch
+	Stream(ctx context.Context, conds ...interface{}) (<-chan T, <-chan error)                                                       // Stream matching records one at a time
+	OpenCursor(ctx context.Context, conds ...interface{}) (*Cursor[T], error)                                                        // Open a cursor iterating over matching records one at a time
+	Paginate(ctx context.Context, models *[]T, page, pageSize int, conds ...interface{}) error                                       // Select query with offset and limit
+	FindPaginated(ctx context.Context, page, pageSize int, conds ...interface{}) (*Page[T], error)                                   // Paginated select query with pagination metadata
+	FindAndCount(ctx context.Context, models *[]T, page, pageSize int, conds ...interface{}) (int64, error)                          // Paginated select query returning the count of matching records
+	FindAfter(ctx context.Context, models *[]T, cursorColumn string, cursorValue interface{}, limit int, conds ...interface{}) error // Keyset paginated select query
+	Search(ctx context.Context, models *[]T, columns []string, term string, conds ...interface{}) error                              // Find the records one of columns of contains term case insensitively
+	RunInTransaction(ctx context.Context, fn func(txRepo IRepository[T]) error) error                                                // Run fn inside a transaction
+	WithTx(tx *gorm.DB) IRepository[T]                                                                                               // Get a copy of repository bound to an existing transaction
+	Begin(ctx context.Context) (IRepository[T], error)                                                                               // Begin a transaction and get a copy of repository bound to it
+	Commit() error                                                                                                                   // Commit the transaction repository is bound to
+	Rollback() error                                                                                                                 // Rollback the transaction repository is bound to
+	SavePoint(name string) error                                                                                                     // Set a savepoint inside the transaction repository is bound to
+	RollbackTo(name string) error                                                                                                    // Rollback to a savepoint inside the transaction repository is bound to
+	SubQuery(opts ...QueryOption) *gorm.DB                                                                                           // Build a query over the model to use as a subquery
+	SetLogger(logger Logger)                                                                                                         // Set the logger observing repository operations
+	SetTracer(tracer Tracer)                                                                                                         // Set the tracer starting a span for each repository operation
+	SetMetrics(metrics MetricsCollector)                                                                                             // Set the collector recording metrics of repository operations
+	SetSlowThreshold(threshold time.Duration)                                                                                        // Set the duration above which operations are reported to OnSlowQuery
+	OnSlowQuery(fn func(op string, d time.Duration))                                                                                 // Set the callback called with operations slower than the slow threshold
+	SetSoftDeleteColumn(column string)                                                                                               // Set the column soft deleting records, for schemas not using gorm.DeletedAt
+	WithActor(actorID interface{}) IRepository[T]                                                                                    // Get a copy of the repository stamping actorID into CreatedBy and UpdatedBy
+	WithTenant(tenantColumn string, tenantID interface{}) IRepository[T]                                                             // Get a copy of the repository scoped to the tenant tenantID
+	SetTimestampColumns(createdColumn, updatedColumn string)                                                                         // Set the non-standard timestamp columns stamped on create and update
+	ExplainSQL(op func(db *gorm.DB) *gorm.DB) string                                                                                 // Get the SQL op would run with its args bound, without running it
+	AutoMigrate() error                                                                                                              // Create or update the table of the model
+	Ping(ctx context.Context) error                                                                                                  // Verify the database is reachable
+	SetReadWriteSplitting(enabled bool)                                                                                              // Route reads to the replicas and writes to the primary with dbresolver
+	WithReadOnly() IRepository[T]                                                                                                    // Get a copy of the repository routing its reads to the replicas
+	SetCache(cache Cache, ttl time.Duration)                                                                                         // Set the cache First and Find results are served from
+	WithRetry(maxAttempts int, backoff time.Duration) IRepository[T]                                                                 // Get a copy of the repository retrying writes failing with transient errors
+	SetRetryable(retryable func(err error) bool)                                                                                     // Set the func reporting whether a write error is retryable
+	ConfigurePool(maxOpen, maxIdle int, connMaxLifetime time.Duration) error                                                         // Set the connection pool limits of the database
+	CountAssociation(ctx context.Context, model *T, association string) (int64, error)                                               // Count the records related to value through association
+	FindAssociated(ctx context.Context, model *T, association string, dest interface{}) error                                        // Load the records related to value through association into dest
+	AppendAssociation(ctx context.Context, model *T, association string, values ...interface{}) error                                // Add values to the records related to value through association
+	ReplaceAssociation(ctx context.Context, model *T, association string, values ...interface{}) error                               // Replace the records related to value through association
+	DeleteAssociation(ctx context.Context, model *T, association string, values ...interface{}) error                                // Remove the links between value and values through association
+	WithScope(scope func(*gorm.DB) *gorm.DB) IRepository[T]                                                                          // Get a copy of the repository applying scope to every read
+	MaskColumns(columns ...string)                                                                                                   // Set the columns omitted by reads, like password hashes
+	SetEncryption(encryptor FieldEncryptor, fields ...string)                                                                        // Set the fields stored encrypted with encryptor
+	Clone(db *gorm.DB) IRepository[T]                                                                                                // Get a copy of the repository bound to another database
+	WithTable(name string) IRepository[T]                                                                                            // Get a copy of the repository querying the table name instead of the model table
+	WithSchema(schema string) IRepository[T]                                                                                         // Get a copy of the repository querying the model table in the database schema
+	SetDebug(enabled bool)                                                                                                           // Enable the detection of N+1 queries registered with EnableNPlusOneDetection
+	SetNPlusOneThreshold(threshold int)                                                                                              // Set the number of times a query can run during a logical operation before it's reported as N+1
+	OnNPlusOne(fn func(query string, count int))                                                                                     // Set the callback called with the N+1 queries detected in debug mode
+	SetDefaultTimeout(d time.Duration)                                                                                               // Set the timeout of the operations called with a context without deadline
+	GetDB() *gorm.DB                                                                                                                 // Get Database Instance
 }
 
 // Repository a generic struct which should be embed by other repositories
@@ -32,9 +140,46 @@ type IRepository[T IBaseModel] interface {
 //		Repository[SampleModel]
 //	}
 type Repository[T IBaseModel] struct {
-	IRepository[T]
-
 	Database *gorm.DB
+
+	logger  Logger
+	tracer  Tracer
+	metrics MetricsCollector
+
+	slowThreshold time.Duration
+	onSlowQuery   func(op string, d time.Duration)
+
+	softDeleteColumn string
+	actor            interface{}
+	tenantColumn     string
+	tenantID         interface{}
+	createdColumn    string
+	updatedColumn    string
+
+	readWriteSplitting bool
+	readOnly           bool
+
+	cache    Cache
+	cacheTTL time.Duration
+
+	maxAttempts int
+	backoff     time.Duration
+	retryable   func(err error) bool
+
+	scopes        []func(*gorm.DB) *gorm.DB
+	maskedColumns []string
+
+	encryptor       FieldEncryptor
+	encryptedFields []string
+
+	tableName  string
+	schemaName string
+
+	debug             bool
+	nPlusOneThreshold int
+	onNPlusOne        func(query string, count int)
+
+	timeout time.Duration
 }
 
 // InitRepository use this in cases you don't want to embed Repository in your Repository structs
@@ -53,94 +198,568 @@ type IBaseModel interface {
 }
 
 // First finds the first record ordered by primary key, matching given conditions
-func (r *Repository[T]) First(model *T, conds ...interface{}) error {
-	res := r.Database.First(&model, conds...)
+func (r *Repository[T]) First(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "First", func(ctx context.Context) (int64, error) {
+		rows, err := r.cachedQuery(r.query(ctx, conds), model, func(db *gorm.DB) *gorm.DB {
+			return db.First(model)
+		})
 
-	if res.Error != nil && res.Error != gorm.ErrRecordNotFound {
-		return res.Error
-	}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return 0, err
+		}
 
-	return nil
+		return rows, r.decrypt(ctx, model)
+	})
 }
 
-// FirstOrFail finds the first record ordered by primary key, matching given conditions
-func (r *Repository[T]) FirstOrFail(model *T, conds ...interface{}) error {
-	res := r.Database.First(&model, conds...)
+// FirstOrFail finds the first record ordered by primary key, matching given conditions,
+// returns ErrNotFound if finds nothing
+func (r *Repository[T]) FirstOrFail(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "FirstOrFail", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).First(model)
 
-	if res.Error != nil {
-		return res.Error
-	}
+		if res.Error != nil {
+			return 0, notFound(res.Error)
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// Last finds the last record ordered by primary key, matching given conditions
+func (r *Repository[T]) Last(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "Last", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Last(model)
+
+		if res.Error != nil && res.Error != gorm.ErrRecordNotFound {
+			return 0, res.Error
+		}
 
-	return nil
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// LastOrFail finds the last record ordered by primary key, matching given conditions,
+// returns ErrNotFound if finds nothing
+func (r *Repository[T]) LastOrFail(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "LastOrFail", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Last(model)
+
+		if res.Error != nil {
+			return 0, notFound(res.Error)
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// Take finds a record matching given conditions, unlike First no ordering is applied
+// so the database is free to return any matching record
+func (r *Repository[T]) Take(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "Take", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Take(model)
+
+		if res.Error != nil && res.Error != gorm.ErrRecordNotFound {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// TakeOrFail finds a record matching given conditions, unlike FirstOrFail no ordering is applied
+// so the database is free to return any matching record, returns ErrNotFound if finds nothing
+func (r *Repository[T]) TakeOrFail(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "TakeOrFail", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Take(model)
+
+		if res.Error != nil {
+			return 0, notFound(res.Error)
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
 }
 
 // Find finds the all the records ordered by primary key, matching given conditions
-func (r *Repository[T]) Find(models *[]T, conds ...interface{}) error {
-	res := r.Database.Find(&models, conds...)
+func (r *Repository[T]) Find(ctx context.Context, models *[]T, conds ...interface{}) error {
+	return r.run(ctx, "Find", func(ctx context.Context) (int64, error) {
+		rows, err := r.cachedQuery(r.query(ctx, conds), models, func(db *gorm.DB) *gorm.DB {
+			return db.Find(models)
+		})
 
-	if res.Error != nil && res.Error != gorm.ErrRecordNotFound {
-		return res.Error
-	}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return 0, err
+		}
 
-	return nil
+		return rows, r.decrypt(ctx, models)
+	})
 }
 
-// FindOrFail finds the all the records ordered by primary key, matching given conditions
-func (r *Repository[T]) FindOrFail(models *[]T, conds ...interface{}) error {
-	res := r.Database.Find(&models, conds...)
+// FindOrFail finds the all the records ordered by primary key, matching given conditions,
+// returns ErrNotFound if finds nothing. Since GORM's Find doesn't fail on an empty result,
+// the not found error is returned whenever models ends up empty.
+func (r *Repository[T]) FindOrFail(ctx context.Context, models *[]T, conds ...interface{}) error {
+	return r.run(ctx, "FindOrFail", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Find(models)
 
-	if res.Error != nil {
-		return res.Error
-	}
+		if res.Error != nil {
+			return 0, notFound(res.Error)
+		}
+
+		if len(*models) == 0 {
+			return 0, notFound(gorm.ErrRecordNotFound)
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, models)
+	})
+}
+
+// FindByID finds the record whose primary key equals id, the primary key column is
+// resolved from the model schema so any primary key type like uuid strings works
+func (r *Repository[T]) FindByID(ctx context.Context, model *T, id interface{}) error {
+	return r.run(ctx, "FindByID", func(ctx context.Context) (int64, error) {
+		cond, err := r.byID(id)
+
+		if err != nil {
+			return 0, err
+		}
 
-	return nil
+		return 0, r.First(ctx, model, cond)
+	})
+}
+
+// FindByIDOrFail finds the record whose primary key equals id, the primary key column is
+// resolved from the model schema so any primary key type like uuid strings works,
+// returns ErrNotFound if finds nothing
+func (r *Repository[T]) FindByIDOrFail(ctx context.Context, model *T, id interface{}) error {
+	return r.run(ctx, "FindByIDOrFail", func(ctx context.Context) (int64, error) {
+		cond, err := r.byID(id)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, r.FirstOrFail(ctx, model, cond)
+	})
+}
+
+// FindByIDs finds the records whose primary key is one of ids, ids should be a slice
+// of the primary key type or a single id like an uuid.UUID. models is set to an empty slice if none matches.
+func (r *Repository[T]) FindByIDs(ctx context.Context, models *[]T, ids interface{}) error {
+	return r.run(ctx, "FindByIDs", func(ctx context.Context) (int64, error) {
+		column, err := r.primaryKey()
+
+		if err != nil {
+			return 0, err
+		}
+
+		values := toSlice(ids)
+
+		if len(values) == 0 {
+			*models = []T{}
+			return 0, nil
+		}
+
+		return 0, r.Find(ctx, models, clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Values: values})
+	})
+}
+
+// FindByCompositeKey finds the record of a model with a multi-column primary key, keys maps
+// every primary key column or field name to its value:
+//
+//	repo.FindByCompositeKey(ctx, &userRole, map[string]interface{}{"user_id": 1, "role_id": 2})
+//
+// returns ErrInvalidCompositeKey if keys aren't exactly the primary keys of the model
+func (r *Repository[T]) FindByCompositeKey(ctx context.Context, model *T, keys map[string]interface{}) error {
+	return r.run(ctx, "FindByCompositeKey", func(ctx context.Context) (int64, error) {
+		cond, err := r.byCompositeKey(keys)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, r.First(ctx, model, cond)
+	})
+}
+
+// FirstBy finds the first record ordered by primary key whose column equals value,
+// column is validated against the model schema
+func (r *Repository[T]) FirstBy(ctx context.Context, model *T, column string, value interface{}) error {
+	return r.run(ctx, "FirstBy", func(ctx context.Context) (int64, error) {
+		cond, err := r.equals(column, value)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, r.First(ctx, model, cond)
+	})
+}
+
+// FindBy finds all the records whose column equals value,
+// column is validated against the model schema
+func (r *Repository[T]) FindBy(ctx context.Context, models *[]T, column string, value interface{}) error {
+	return r.run(ctx, "FindBy", func(ctx context.Context) (int64, error) {
+		cond, err := r.equals(column, value)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, r.Find(ctx, models, cond)
+	})
+}
+
+// FindByFilters finds all the records whose columns equal the values of filters, nil values match NULL columns.
+// Keys are validated against the model schema and the unknown ones are ignored, so filters can come from a request:
+//
+//	repo.FindByFilters(ctx, &users, map[string]interface{}{"status": "active", "deleted_by": nil})
+func (r *Repository[T]) FindByFilters(ctx context.Context, models *[]T, filters map[string]interface{}) error {
+	return r.run(ctx, "FindByFilters", func(ctx context.Context) (int64, error) {
+		columns := make([]string, 0, len(filters))
+
+		for column := range filters {
+			columns = append(columns, column)
+		}
+
+		sort.Strings(columns)
+		exprs := make([]clause.Expression, 0, len(columns))
+
+		for _, column := range columns {
+			cond, err := r.equals(column, filters[column])
+
+			if errors.Is(err, ErrInvalidColumn) {
+				continue
+			}
+
+			if err != nil {
+				return 0, err
+			}
+
+			exprs = append(exprs, cond)
+		}
+
+		if len(exprs) == 0 {
+			return 0, r.Find(ctx, models)
+		}
+
+		return 0, r.Find(ctx, models, clause.And(exprs...))
+	})
 }
 
 // Create inserts value, returning the inserted data's primary key in value's id
-func (r *Repository[T]) Create(model *T) (*T, error) {
-	res := r.Database.Create(model)
+func (r *Repository[T]) Create(ctx context.Context, model *T) (*T, error) {
+	err := r.run(ctx, "Create", func(ctx context.Context) (int64, error) {
+		if err := r.prepareCreate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return createHooks(ctx, []*T{model}, r.encrypted(ctx, []*T{model}, r.retried(ctx, func() (int64, error) {
+			res := r.db(ctx).Create(model)
+
+			return res.RowsAffected, res.Error
+		})))
+	})
 
-	if res.Error != nil {
-		return nil, res.Error
+	if err != nil {
+		return nil, err
 	}
 
 	return model, nil
 }
 
-// BulkCreate Create inserts value, returning the inserted data's primary key in value's id
-func (r *Repository[T]) BulkCreate(models []*T) (int64, error) {
-	res := r.Database.Create(models)
+// CreateOmit inserts value without writing the omitted columns, returning the inserted data's primary key in value's id
+func (r *Repository[T]) CreateOmit(ctx context.Context, model *T, omit ...string) (*T, error) {
+	err := r.run(ctx, "CreateOmit", func(ctx context.Context) (int64, error) {
+		if err := r.prepareCreate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
 
-	if res.Error != nil {
-		return res.RowsAffected, res.Error
+		return createHooks(ctx, []*T{model}, r.encrypted(ctx, []*T{model}, r.retried(ctx, func() (int64, error) {
+			res := r.db(ctx).Omit(omit...).Create(model)
+
+			return res.RowsAffected, res.Error
+		})))
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return res.RowsAffected, nil
+	return model, nil
 }
 
-// Update Save updates value in database. If value doesn't contain a matching primary key, value is inserted.
-func (r *Repository[T]) Update(model *T) error {
-	res := r.Database.Save(model)
+// CreateReturning inserts value and reads back the given columns from the inserted row with RETURNING,
+// so values generated by the database like defaults or computed columns are set in value.
+// The primary key is always read back, all the columns are when columns is empty. RETURNING is supported by Postgres, SQLite and MariaDB.
+func (r *Repository[T]) CreateReturning(ctx context.Context, model *T, columns ...string) (*T, error) {
+	err := r.run(ctx, "CreateReturning", func(ctx context.Context) (int64, error) {
+		returning, err := r.returning(columns)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err := r.prepareCreate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return createHooks(ctx, []*T{model}, r.encrypted(ctx, []*T{model}, r.retried(ctx, func() (int64, error) {
+			res := r.db(ctx).Clauses(returning).Create(model)
+
+			return res.RowsAffected, res.Error
+		})))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FirstOrCreate finds the first record ordered by primary key matching given conditions,
+// if finds nothing inserts value initialized with the conditions. created reports whether value was inserted.
+func (r *Repository[T]) FirstOrCreate(ctx context.Context, model *T, conds ...interface{}) (*T, bool, error) {
+	var created bool
+	err := r.run(ctx, "FirstOrCreate", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).FirstOrInit(model)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		if res.RowsAffected > 0 {
+			return res.RowsAffected, r.decrypt(ctx, model)
+		}
+
+		if _, err := r.Create(ctx, model); err != nil {
+			return 0, err
+		}
+
+		created = true
+
+		return 0, nil
+	})
 
-	if res.Error != nil {
-		return res.Error
+	if err != nil {
+		return nil, false, err
 	}
 
-	return nil
+	return model, created, nil
+}
+
+// FirstOrInit finds the first record ordered by primary key matching given conditions, if finds
+// nothing initializes value with the conditions without inserting it, struct and map conditions are assigned
+func (r *Repository[T]) FirstOrInit(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "FirstOrInit", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).FirstOrInit(model)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return 0, nil
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// BatchCreate inserts all the models in a single statement, returning the inserted data's primary keys in models' id
+func (r *Repository[T]) BatchCreate(ctx context.Context, models []*T) (int64, error) {
+	return r.runRows(ctx, "BatchCreate", func(ctx context.Context) (int64, error) {
+		if err := r.prepareCreate(ctx, models); err != nil {
+			return 0, err
+		}
+
+		return createHooks(ctx, models, r.encrypted(ctx, models, r.retried(ctx, func() (int64, error) {
+			res := r.db(ctx).Create(models)
+
+			return res.RowsAffected, res.Error
+		})))
+	})
+}
+
+// BatchCreateInChunks inserts models chunkSize models per statement inside a single transaction, so a failing chunk
+// rolls back the ones inserted before it, and returns rows affected. chunkSize <= 0 falls back to DefaultPageSize.
+func (r *Repository[T]) BatchCreateInChunks(ctx context.Context, models []*T, chunkSize int) (int64, error) {
+	_, chunkSize = normalizePage(DefaultPage, chunkSize)
+
+	return r.runRows(ctx, "BatchCreateInChunks", func(ctx context.Context) (int64, error) {
+		if err := r.prepareCreate(ctx, models); err != nil {
+			return 0, err
+		}
+
+		return createHooks(ctx, models, r.encrypted(ctx, models, r.retried(ctx, func() (int64, error) {
+			var rows int64
+			err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+				res := tx.CreateInBatches(models, chunkSize)
+				rows = res.RowsAffected
+
+				return res.Error
+			})
+
+			return rows, err
+		})))
+	})
+}
+
+// Update Save updates value in database. If value doesn't contain a matching primary key, value is inserted,
+// use UpdateExisting to only update existing records.
+// If value includes an integer Version field, the update only matches the record with the same version
+// and bumps it, ErrOptimisticLock is returned if the record was changed meanwhile.
+func (r *Repository[T]) Update(ctx context.Context, model *T) error {
+	return r.run(ctx, "Update", func(ctx context.Context) (int64, error) {
+		if err := r.prepareUpdate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return updateHooks(ctx, []*T{model}, r.encrypted(ctx, []*T{model}, func() (int64, error) {
+			return r.save(ctx, r.db(ctx), model)
+		}))
+	})
+}
+
+// UpdateOmit Save updates value in database without touching the omitted columns.
+// If value doesn't contain a matching primary key, value is inserted.
+// Version fields are handled the same as Update.
+func (r *Repository[T]) UpdateOmit(ctx context.Context, model *T, omit ...string) error {
+	return r.run(ctx, "UpdateOmit", func(ctx context.Context) (int64, error) {
+		if err := r.prepareUpdate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return updateHooks(ctx, []*T{model}, r.encrypted(ctx, []*T{model}, func() (int64, error) {
+			return r.save(ctx, r.db(ctx).Omit(omit...), model)
+		}))
+	})
+}
+
+// UpdateExisting updates value in database like Update and returns rows affected, unlike Update it never
+// inserts value. Returns ErrNotFound if value has no primary key or no record has its primary key.
+// Version fields are handled the same as Update.
+func (r *Repository[T]) UpdateExisting(ctx context.Context, model *T) (int64, error) {
+	return r.runRows(ctx, "UpdateExisting", func(ctx context.Context) (int64, error) {
+		if err := r.prepareUpdate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return updateHooks(ctx, []*T{model}, r.encrypted(ctx, []*T{model}, func() (int64, error) {
+			return r.updateExisting(ctx, r.db(ctx), model)
+		}))
+	})
 }
 
 // Delete deletes value matching given conditions.
-// If value contains primary key it is included in the conditions.
+// If value contains primary key it is included in the conditions, all the columns of a composite primary key are.
 // If value includes a deleted_at field, then Delete performs a soft delete
 // instead by setting deleted_at with the current time if null.
-func (r *Repository[T]) Delete(model *T) (int64, error) {
-	res := r.Database.Delete(model)
+func (r *Repository[T]) Delete(ctx context.Context, model *T) (int64, error) {
+	return r.runRows(ctx, "Delete", func(ctx context.Context) (int64, error) {
+		return deleteHooks(ctx, []*T{model}, func() (int64, error) {
+			db, err := r.keyed(ctx, r.db(ctx), model)
+
+			if err != nil {
+				return 0, err
+			}
+
+			res := r.remove(db, model)
+
+			return res.RowsAffected, res.Error
+		})
+	})
+}
+
+// Count counts the records matching given conditions.
+// Soft deleted records are not counted.
+func (r *Repository[T]) Count(ctx context.Context, conds ...interface{}) (int64, error) {
+	var count int64
+	err := r.run(ctx, "Count", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Count(&count)
 
-	if res.Error != nil {
 		return res.RowsAffected, res.Error
+	})
+
+	if err != nil {
+		return 0, err
 	}
 
-	return res.RowsAffected, nil
+	return count, nil
+}
+
+// Exists reports whether at least one record matches given conditions.
+// It issues a SELECT 1 ... LIMIT 1 query, finding nothing is not an error.
+func (r *Repository[T]) Exists(ctx context.Context, conds ...interface{}) (bool, error) {
+	var exists int
+	var found bool
+	err := r.run(ctx, "Exists", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Select("1").Limit(1).Scan(&exists)
+		found = res.RowsAffected > 0
+
+		return res.RowsAffected, res.Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// ExistsBy reports whether at least one record has column equal to value, column is validated
+// against the model schema. Like Exists it issues a SELECT 1 ... LIMIT 1 query:
+//
+//	taken, err := repo.ExistsBy(ctx, "email", email)
+func (r *Repository[T]) ExistsBy(ctx context.Context, column string, value interface{}) (bool, error) {
+	var found bool
+	err := r.run(ctx, "ExistsBy", func(ctx context.Context) (int64, error) {
+		cond, err := r.equals(column, value)
+
+		if err != nil {
+			return 0, err
+		}
+
+		found, err = r.Exists(ctx, cond)
+
+		return 0, err
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// db returns the database handle bound to ctx so deadlines and cancellation
+// propagate into the query
+func (r *Repository[T]) db(ctx context.Context) *gorm.DB {
+	return r.resolve(ctx, r.scoped(r.Database.WithContext(r.debugged(ctx))))
+}
+
+// query returns the database handle bound to ctx reading the repository model, with its scopes,
+// masked columns and the query options and inline conditions found in conds applied
+func (r *Repository[T]) query(ctx context.Context, conds []interface{}) *gorm.DB {
+	return where(r.readScoped(r.db(ctx)).Model(new(T)), conds)
+}
+
+// Clone returns a copy of the repository bound to db, like the database of a tenant, keeping
+// its configuration like the logger, cache or scopes. The original repository is left untouched:
+//
+//	tenantRepo := repo.Clone(tenantDB)
+func (r *Repository[T]) Clone(db *gorm.DB) IRepository[T] {
+	return r.withDB(db)
+}
+
+// withDB returns a copy of the repository bound to db, keeping its configuration
+func (r *Repository[T]) withDB(db *gorm.DB) *Repository[T] {
+	clone := *r
+	clone.Database = db
+
+	return &clone
 }
 
 // GetDB return *gorm.DB for other methods which this repository doesn't support it
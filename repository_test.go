@@ -0,0 +1,630 @@
+package regorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestCancelledContext(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	seed(t, repo, "alice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var user User
+	var users []User
+
+	if err := repo.First(ctx, &user); !errors.Is(err, context.Canceled) {
+		t.Errorf("First: got %v, want context.Canceled", err)
+	}
+
+	if err := repo.Find(ctx, &users); !errors.Is(err, context.Canceled) {
+		t.Errorf("Find: got %v, want context.Canceled", err)
+	}
+
+	if _, err := repo.Create(ctx, &User{Name: "bob"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Create: got %v, want context.Canceled", err)
+	}
+
+	if err := repo.Update(ctx, &User{ID: 1, Name: "carol"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Update: got %v, want context.Canceled", err)
+	}
+
+	if _, err := repo.Delete(ctx, &User{ID: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Delete: got %v, want context.Canceled", err)
+	}
+
+	if err := repo.First(context.Background(), &user, 1); err != nil || user.Name != "alice" {
+		t.Errorf("got %+v, %v, want alice untouched", user, err)
+	}
+}
+
+func TestCount(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice", "bob", "bob", "carol")
+
+	n, err := repo.Count(ctx)
+
+	if err != nil || n != 4 {
+		t.Fatalf("got %d, %v, want 4", n, err)
+	}
+
+	n, err = repo.Count(ctx, map[string]interface{}{"name": "bob"})
+
+	if err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2", n, err)
+	}
+
+	if _, err := repo.Delete(ctx, users[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err = repo.Count(ctx, map[string]interface{}{"name": "bob"})
+
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want soft deleted record not counted", n, err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice")
+
+	found, err := repo.Exists(ctx, users[0].ID)
+
+	if err != nil || !found {
+		t.Fatalf("got %v, %v, want true", found, err)
+	}
+
+	found, err = repo.Exists(ctx, users[0].ID+100)
+
+	if err != nil || found {
+		t.Fatalf("got %v, %v, want false without error", found, err)
+	}
+
+	if _, err := repo.Exists(ctx, "missing_column = ?", 1); err == nil {
+		t.Fatal("want the database error")
+	}
+}
+
+func TestExistsBy(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seed(t, repo, "alice")
+	rec := record(t, db)
+
+	if taken, err := repo.ExistsBy(ctx, "email", "alice@example.com"); err != nil || !taken {
+		t.Fatalf("got %v, %v, want true", taken, err)
+	}
+
+	if sql := rec.last(); sql != "SELECT 1 FROM `users` WHERE `users`.`email` = ? AND `users`.`deleted_at` IS NULL LIMIT 1" {
+		t.Fatalf("got %q, want a single row query on the column", sql)
+	}
+
+	if taken, err := repo.ExistsBy(ctx, "email", "bob@example.com"); err != nil || taken {
+		t.Fatalf("got %v, %v, want false", taken, err)
+	}
+
+	if _, err := repo.ExistsBy(ctx, "email = email OR 1", 1); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestBatchCreate(t *testing.T) {
+	var repo IRepository[User] = InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := []*User{{Name: "alice"}, {Name: "bob"}, {Name: "carol"}}
+
+	n, err := repo.BatchCreate(ctx, users)
+
+	if err != nil || n != 3 {
+		t.Fatalf("got %d, %v, want 3", n, err)
+	}
+
+	for _, user := range users {
+		if user.ID == 0 {
+			t.Fatalf("got %+v, want the primary key set", user)
+		}
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 3 {
+		t.Fatalf("got %d, %v, want 3 records", count, err)
+	}
+}
+
+func TestBatchCreateInChunks(t *testing.T) {
+	db := openDB(t, &Product{})
+	repo := InitRepository[Product](db)
+	ctx := context.Background()
+	rec := record(t, db)
+	products := make([]*Product, 25)
+
+	for i := range products {
+		products[i] = &Product{Code: fmt.Sprintf("p%02d", i), Price: i}
+	}
+
+	if n, err := repo.BatchCreateInChunks(ctx, products, 10); err != nil || n != 25 {
+		t.Fatalf("got %d, %v, want 25", n, err)
+	}
+
+	if n := rec.executed("INSERT"); n != 3 {
+		t.Fatalf("got %d inserts, want 3", n)
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 25 {
+		t.Fatalf("got %d, %v, want 25 records", count, err)
+	}
+
+	// the last chunk collides with an existing code and rolls back the whole call
+	products = make([]*Product, 15)
+
+	for i := range products {
+		products[i] = &Product{Code: fmt.Sprintf("q%02d", i)}
+	}
+
+	products[14].Code = "p00"
+
+	if _, err := repo.BatchCreateInChunks(ctx, products, 10); err == nil {
+		t.Fatal("got no error, want a duplicated key")
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 25 {
+		t.Fatalf("got %d, %v, want the batch rolled back", count, err)
+	}
+}
+
+// tags is a comma separated list stored as text through sql.Scanner and driver.Valuer
+type tags []string
+
+func (t *tags) Scan(value interface{}) error {
+	var text string
+
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("unsupported tags value %T", value)
+	}
+
+	*t = strings.Split(text, ",")
+
+	return nil
+}
+
+func (t tags) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+type Post struct {
+	ID   uint
+	Tags tags `gorm:"type:text"`
+}
+
+func (Post) TableName() string { return "posts" }
+
+func TestScannerDestination(t *testing.T) {
+	repo := InitRepository[Post](openDB(t, &Post{}))
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &Post{Tags: tags{"go", "sql"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var post Post
+
+	if err := repo.First(ctx, &post); err != nil || !reflect.DeepEqual(post.Tags, tags{"go", "sql"}) {
+		t.Fatalf("First: got %+v, %v", post, err)
+	}
+
+	post = Post{}
+
+	if err := repo.FirstOrFail(ctx, &post); err != nil || !reflect.DeepEqual(post.Tags, tags{"go", "sql"}) {
+		t.Fatalf("FirstOrFail: got %+v, %v", post, err)
+	}
+
+	var posts []Post
+
+	if err := repo.Find(ctx, &posts); err != nil || len(posts) != 1 || !reflect.DeepEqual(posts[0].Tags, tags{"go", "sql"}) {
+		t.Fatalf("Find: got %+v, %v", posts, err)
+	}
+
+	posts = nil
+
+	if err := repo.FindOrFail(ctx, &posts); err != nil || len(posts) != 1 || !reflect.DeepEqual(posts[0].Tags, tags{"go", "sql"}) {
+		t.Fatalf("FindOrFail: got %+v, %v", posts, err)
+	}
+}
+
+func TestLast(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+
+	var user User
+
+	if err := repo.Last(ctx, &user); err != nil || user.ID != 0 {
+		t.Fatalf("got %+v, %v, want not found ignored", user, err)
+	}
+
+	if err := repo.LastOrFail(ctx, &user); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+
+	seed(t, repo, "alice", "bob", "carol")
+
+	if err := repo.Last(ctx, &user); err != nil || user.Name != "carol" {
+		t.Fatalf("got %+v, %v, want the highest primary key", user, err)
+	}
+
+	user = User{}
+
+	if err := repo.LastOrFail(ctx, &user, "name <> ?", "carol"); err != nil || user.Name != "bob" {
+		t.Fatalf("got %+v, %v, want the highest matching primary key", user, err)
+	}
+}
+
+func TestTake(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seed(t, repo, "alice")
+	rec := record(t, db)
+
+	var user User
+
+	if err := repo.Take(ctx, &user); err != nil || user.Name != "alice" {
+		t.Fatalf("got %+v, %v", user, err)
+	}
+
+	if sql := rec.last(); strings.Contains(sql, "ORDER BY") || !strings.Contains(sql, "LIMIT") {
+		t.Fatalf("got %q, want no ORDER BY", sql)
+	}
+
+	user = User{}
+
+	if err := repo.Take(ctx, &user, "name = ?", "bob"); err != nil || user.ID != 0 {
+		t.Fatalf("got %+v, %v, want not found ignored", user, err)
+	}
+
+	if err := repo.TakeOrFail(ctx, &user, "name = ?", "bob"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestFirstBy(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "a", "b", "b")
+
+	var user User
+
+	if err := repo.FirstBy(ctx, &user, "email", "a@example.com"); err != nil || user.Name != "a" {
+		t.Fatalf("got %+v, %v, want a", user, err)
+	}
+
+	var users []User
+
+	if err := repo.FindBy(ctx, &users, "name", "b"); err != nil || len(users) != 2 {
+		t.Fatalf("got %+v, %v, want both b", users, err)
+	}
+
+	if err := repo.FindBy(ctx, &users, "name = name OR 1", 1); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestFirstOrCreate(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice")
+
+	user, created, err := repo.FirstOrCreate(ctx, &User{}, User{Name: "alice"})
+
+	if err != nil || created || user.ID != 1 || user.Email != "alice@example.com" {
+		t.Fatalf("got %+v, %v, %v, want alice found", user, created, err)
+	}
+
+	user, created, err = repo.FirstOrCreate(ctx, &User{Email: "bob@example.com"}, User{Name: "bob"})
+
+	if err != nil || !created || user.ID == 0 || user.Name != "bob" || user.Email != "bob@example.com" {
+		t.Fatalf("got %+v, %v, %v, want bob created", user, created, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2 records", n, err)
+	}
+}
+
+func TestFirstOrInit(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice")
+
+	var user User
+
+	if err := repo.FirstOrInit(ctx, &user, User{Name: "alice"}); err != nil || user.ID != 1 || user.Email != "alice@example.com" {
+		t.Fatalf("got %+v, %v, want alice found", user, err)
+	}
+
+	user = User{Age: 30}
+
+	if err := repo.FirstOrInit(ctx, &user, map[string]interface{}{"name": "bob"}); err != nil || user.ID != 0 || user.Name != "bob" || user.Age != 30 {
+		t.Fatalf("got %+v, %v, want bob initialized", user, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want bob not persisted", n, err)
+	}
+}
+
+type Token struct {
+	Key   string `gorm:"primaryKey"`
+	Owner string
+}
+
+func (Token) TableName() string { return "tokens" }
+
+func TestFindByID(t *testing.T) {
+	db := openDB(t, &Token{})
+	users := InitRepository[User](db)
+	tokens := InitRepository[Token](db)
+	ctx := context.Background()
+	seed(t, users, "alice", "bob")
+
+	if _, err := tokens.Create(ctx, &Token{Key: "0b7a5a8e-7c4f-4d2e-9a53-6f0c1e4b2d17", Owner: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var user User
+
+	if err := users.FindByID(ctx, &user, 2); err != nil || user.Name != "bob" {
+		t.Fatalf("got %+v, %v, want bob", user, err)
+	}
+
+	var token Token
+
+	if err := tokens.FindByID(ctx, &token, "0b7a5a8e-7c4f-4d2e-9a53-6f0c1e4b2d17"); err != nil || token.Owner != "alice" {
+		t.Fatalf("got %+v, %v, want the token of alice", token, err)
+	}
+
+	token = Token{}
+
+	if err := tokens.FindByID(ctx, &token, "missing"); err != nil || token.Owner != "" {
+		t.Fatalf("got %+v, %v, want not found ignored", token, err)
+	}
+
+	if err := tokens.FindByIDOrFail(ctx, &token, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+
+	if err := users.FindByIDOrFail(ctx, &user, 3); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestFindByIDs(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "a", "b", "c", "d", "e")
+
+	if _, err := repo.Delete(ctx, users[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	var found []User
+
+	if err := repo.FindByIDs(ctx, &found, []uint{1, 3, 5}); err != nil || len(found) != 3 {
+		t.Fatalf("got %+v, %v, want 3 records", found, err)
+	}
+
+	if err := repo.FindByIDs(ctx, &found, []uint{3, 4}); err != nil || len(found) != 1 || found[0].ID != 3 {
+		t.Fatalf("got %+v, %v, want the soft deleted record skipped", found, err)
+	}
+
+	if err := repo.FindByIDs(ctx, &found, []uint{98, 99}); err != nil || found == nil || len(found) != 0 {
+		t.Fatalf("got %#v, %v, want an empty slice", found, err)
+	}
+
+	if err := repo.FindByIDs(ctx, &found, []uint{}); err != nil || found == nil || len(found) != 0 {
+		t.Fatalf("got %#v, %v, want an empty slice", found, err)
+	}
+}
+
+// Contact has a nullable column
+type Contact struct {
+	ID    uint
+	Name  string
+	Phone *string
+}
+
+func (Contact) TableName() string { return "contacts" }
+
+func TestFindByFilters(t *testing.T) {
+	ctx := context.Background()
+	users := InitRepository[User](openDB(t))
+	seed(t, users, "alice", "bob", "bob")
+
+	var found []User
+
+	if err := users.FindByFilters(ctx, &found, map[string]interface{}{"name": "bob", "age": 22, "password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(found) != 1 || found[0].Name != "bob" || found[0].Age != 22 {
+		t.Fatalf("got %+v, want the second bob with the unknown key ignored", found)
+	}
+
+	phone := "555-0100"
+	contacts := InitRepository[Contact](openDB(t, &Contact{}))
+
+	if _, err := contacts.BatchCreate(ctx, []*Contact{{Name: "alice", Phone: &phone}, {Name: "bob"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var unreachable []Contact
+
+	if err := contacts.FindByFilters(ctx, &unreachable, map[string]interface{}{"phone": nil}); err != nil || len(unreachable) != 1 || unreachable[0].Name != "bob" {
+		t.Fatalf("got %+v, %v, want bob matched by IS NULL", unreachable, err)
+	}
+}
+
+func TestFindOrFail(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice")
+
+	var users []User
+
+	if err := repo.FindOrFail(ctx, &users, "name = ?", "bob"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound on an empty result", err)
+	}
+
+	if err := repo.FindOrFail(ctx, &users, "name = ?", "alice"); err != nil || len(users) != 1 {
+		t.Fatalf("got %+v, %v, want alice", users, err)
+	}
+}
+
+type Session struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name string
+}
+
+func (Session) TableName() string { return "sessions" }
+
+func TestUUIDPrimaryKey(t *testing.T) {
+	repo := InitRepository[Session](openDB(t, &Session{}))
+	ctx := context.Background()
+	a, b := &Session{ID: uuid.New(), Name: "a"}, &Session{ID: uuid.New(), Name: "b"}
+
+	if _, err := repo.BatchCreate(ctx, []*Session{a, b}); err != nil {
+		t.Fatal(err)
+	}
+
+	var session Session
+
+	if err := repo.FindByIDOrFail(ctx, &session, b.ID); err != nil || session.Name != "b" {
+		t.Fatalf("FindByIDOrFail: got %+v, %v, want b", session, err)
+	}
+
+	session = Session{}
+
+	if err := repo.FirstOrFail(ctx, &session, b.ID); err != nil || session.Name != "b" {
+		t.Fatalf("FirstOrFail: got %+v, %v, want b", session, err)
+	}
+
+	var sessions []Session
+
+	if err := repo.FindByIDs(ctx, &sessions, b.ID); err != nil || len(sessions) != 1 {
+		t.Fatalf("FindByIDs: got %+v, %v, want b", sessions, err)
+	}
+
+	if err := repo.FindByIDs(ctx, &sessions, []uuid.UUID{a.ID, b.ID}); err != nil || len(sessions) != 2 {
+		t.Fatalf("FindByIDs: got %+v, %v, want both", sessions, err)
+	}
+
+	b.Name = "renamed"
+
+	if err := repo.Update(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.DeleteByID(ctx, a.ID); err != nil || n != 1 {
+		t.Fatalf("DeleteByID: got %d, %v, want 1 row affected", n, err)
+	}
+
+	if n, err := repo.Delete(ctx, b); err != nil || n != 1 {
+		t.Fatalf("Delete: got %d, %v, want 1 row affected", n, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want both deleted", n, err)
+	}
+}
+
+// Receipt has columns computed by the database
+type Receipt struct {
+	ID       uint
+	Price    int
+	Total    int       `gorm:"->;type:integer GENERATED ALWAYS AS (price * 2) VIRTUAL"`
+	IssuedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (Receipt) TableName() string { return "receipts" }
+
+func TestCreateReturning(t *testing.T) {
+	repo := InitRepository[Receipt](openDB(t, &Receipt{}))
+	ctx := context.Background()
+
+	receipt, err := repo.CreateReturning(ctx, &Receipt{Price: 21})
+
+	if err != nil || receipt.ID == 0 || receipt.Total != 42 || receipt.IssuedAt.IsZero() {
+		t.Fatalf("got %+v, %v, want the computed and default columns read back", receipt, err)
+	}
+
+	receipt, err = repo.CreateReturning(ctx, &Receipt{Price: 5}, "total")
+
+	if err != nil || receipt.ID == 0 || receipt.Total != 10 {
+		t.Fatalf("got %+v, %v, want total and the primary key read back", receipt, err)
+	}
+
+	if _, err := repo.CreateReturning(ctx, &Receipt{}, "missing"); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+
+	db := openDialect(t, "postgres", &Receipt{})
+	rec := record(t, db)
+
+	if _, err := InitRepository[Receipt](db).CreateReturning(ctx, &Receipt{Price: 1}, "total", "issued_at"); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); !strings.HasSuffix(sql, "RETURNING `total`,`issued_at`,`id`") {
+		t.Fatalf("got %q, want the columns returned", sql)
+	}
+}
+
+func TestClone(t *testing.T) {
+	ctx := context.Background()
+	original, other := openDB(t), openDB(t)
+	repo := InitRepository[User](original)
+	logger := &fakeLogger{}
+	repo.SetLogger(logger)
+	scoped := repo.WithScope(func(db *gorm.DB) *gorm.DB {
+		return db.Where("age > ?", 20)
+	})
+
+	clone := scoped.Clone(other)
+	seed(t, clone, "alice", "bob")
+
+	if count, err := repo.Count(ctx); err != nil || count != 0 {
+		t.Fatalf("got %d, %v, want the original database untouched", count, err)
+	}
+
+	var users []User
+
+	if err := InitRepository[User](other).Find(ctx, &users); err != nil || len(users) != 2 {
+		t.Fatalf("got %+v, %v, want the users written to the other database", users, err)
+	}
+
+	if err := clone.Find(ctx, &users); err != nil || len(users) != 1 || users[0].Name != "bob" {
+		t.Fatalf("got %+v, %v, want the scope kept", users, err)
+	}
+
+	if ops := logger.observed(); len(ops) != 4 || ops[0] != "Create users" || ops[3] != "Find users" {
+		t.Fatalf("got %v, want the logger kept", ops)
+	}
+}
@@ -0,0 +1,92 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// readOperations are the operations routed to the replicas when read/write splitting is enabled,
+// others like FirstForUpdate or FirstOrCreate force the primary
+var readOperations = map[string]bool{
+	"First": true, "FirstOrFail": true, "Last": true, "LastOrFail": true, "Take": true, "TakeOrFail": true,
+	"Find": true, "FindOrFail": true, "FindByID": true, "FindByIDOrFail": true, "FindByIDs": true,
+	"FindByCompositeKey": true, "FirstBy": true, "FirstOrInit": true, "FindBy": true, "FindByFilters": true,
+	"FirstWithTrashed": true, "FindWithTrashed": true, "FirstOnlyTrashed": true, "FindOnlyTrashed": true, "CountWithTrashed": true,
+	"Count": true, "Exists": true, "ExistsBy": true, "Sum": true, "Avg": true, "Min": true, "Max": true, "GroupCount": true,
+	"Distinct": true, "Pluck": true, "PluckDistinct": true, "ScanInto": true, "FindMaps": true, "Raw": true,
+	"FindInBatches": true, "Stream": true, "OpenCursor": true, "Paginate": true, "FindPaginated": true, "FindAndCount": true,
+	"FindAfter": true, "Search": true, "CountAssociation": true, "FindAssociated": true,
+}
+
+// nonWriteOperations are the operations which neither read nor write the model table
+var nonWriteOperations = map[string]bool{
+	"Ping": true, "ConfigurePool": true, "Begin": true, "Rollback": true, "SavePoint": true, "RollbackTo": true,
+}
+
+// writeOperations are the operations writing to the model table, unlike the locking reads
+// like FirstForUpdate which run on the primary without writing
+var writeOperations = map[string]bool{
+	"Create": true, "CreateOmit": true, "CreateReturning": true, "BatchCreate": true, "BatchCreateInChunks": true,
+	"CreateIgnore": true, "FirstOrCreate": true, "Upsert": true, "UpsertReturning": true, "BatchUpsert": true,
+	"Update": true, "UpdateOmit": true, "UpdateColumns": true, "UpdateWhere": true, "UpdateExisting": true,
+	"Increment": true, "Decrement": true, "Delete": true, "DeleteByID": true, "DeleteWhere": true,
+	"DeleteInChunks": true, "ForceDelete": true, "Restore": true, "Exec": true, "RunInTransaction": true, "Commit": true,
+	"AppendAssociation": true, "ReplaceAssociation": true, "DeleteAssociation": true, "AutoMigrate": true,
+}
+
+// onPrimary reports whether the operation op runs on the primary, the writes and the reads which
+// need the latest data or take locks like FirstForUpdate
+func onPrimary(op string) bool {
+	return !readOperations[op] && !nonWriteOperations[op]
+}
+
+// SetReadWriteSplitting enables routing the read operations of repository like First, Find or Count
+// to the replicas and the write operations to the primary, with the dbresolver plugin registered on the database:
+//
+//	db.Use(dbresolver.Register(dbresolver.Config{
+//		Replicas: []gorm.Dialector{postgres.Open(replicaDSN)},
+//	}))
+//	repo := regorm.InitRepository[User](db)
+//	repo.SetReadWriteSplitting(true)
+//
+// Operations in a transaction always run on the transaction connection.
+func (r *Repository[T]) SetReadWriteSplitting(enabled bool) {
+	r.readWriteSplitting = enabled
+}
+
+// WithReadOnly returns a copy of the repository routing its reads to the replicas whether read/write splitting
+// is enabled or not. The operations forcing the primary with read/write splitting still run on the primary,
+// the writes and the reads like FirstForUpdate or FirstOrCreate which need the latest data or take locks.
+// The original repository is left untouched
+func (r *Repository[T]) WithReadOnly() IRepository[T] {
+	clone := *r
+	clone.readOnly = true
+
+	return &clone
+}
+
+// resolve routes db to the replicas or the primary depending on the operation running in ctx
+func (r *Repository[T]) resolve(ctx context.Context, db *gorm.DB) *gorm.DB {
+	current, ok := ctx.Value(operationKey{}).(*operation)
+
+	if r.readOnly {
+		// the replicas can't be written nor locked, these operations go to the primary whatever the repository
+		if ok && onPrimary(current.name) {
+			return db.Clauses(dbresolver.Write)
+		}
+
+		return db.Clauses(dbresolver.Read)
+	}
+
+	if !r.readWriteSplitting {
+		return db
+	}
+
+	if ok && readOperations[current.name] {
+		return db.Clauses(dbresolver.Read)
+	}
+
+	return db.Clauses(dbresolver.Write)
+}
@@ -0,0 +1,107 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// openReplicated opens a primary and a replica database, registered with the dbresolver plugin on the primary
+func openReplicated(t *testing.T) (primary, replica *gorm.DB) {
+	t.Helper()
+
+	primary, replica = openDB(t), openDB(t)
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{sqlite.Dialector{Conn: replica.ConnPool}},
+	})
+
+	if err := primary.Use(resolver); err != nil {
+		t.Fatal(err)
+	}
+
+	return primary, replica
+}
+
+func TestReadWriteSplitting(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := openReplicated(t)
+	seed(t, InitRepository[User](replica), "replica")
+
+	repo := InitRepository[User](primary)
+	repo.SetReadWriteSplitting(true)
+	seed(t, repo, "primary")
+
+	var user User
+
+	if err := repo.First(ctx, &user); err != nil || user.Name != "replica" {
+		t.Fatalf("First: got %+v, %v, want read from the replica", user, err)
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 1 {
+		t.Fatalf("Count: got %d, %v, want 1", count, err)
+	}
+
+	var locked User
+
+	if err := repo.FirstForUpdate(ctx, &locked); err != nil || locked.Name != "primary" {
+		t.Fatalf("FirstForUpdate: got %+v, %v, want read from the primary", locked, err)
+	}
+
+	var stored User
+
+	if err := primary.Clauses(dbresolver.Write).First(&stored, "name = ?", "primary").Error; err != nil {
+		t.Fatalf("got %v, want the user written to the primary", err)
+	}
+
+	err := repo.RunInTransaction(ctx, func(txRepo IRepository[User]) error {
+		var user User
+
+		if err := txRepo.First(ctx, &user); err != nil || user.Name != "primary" {
+			t.Errorf("in transaction: got %+v, %v, want read from the primary", user, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := openReplicated(t)
+	seed(t, InitRepository[User](replica), "replica")
+
+	repo := InitRepository[User](primary)
+	readOnly := repo.WithReadOnly()
+
+	var user User
+
+	if err := readOnly.First(ctx, &user); err != nil || user.Name != "replica" {
+		t.Fatalf("First: got %+v, %v, want read from the replica", user, err)
+	}
+
+	if _, err := readOnly.Create(ctx, &User{Name: "written"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var locked User
+
+	if err := readOnly.FirstForUpdate(ctx, &locked); err != nil || locked.Name != "written" {
+		t.Fatalf("FirstForUpdate: got %+v, %v, want the locking read on the primary", locked, err)
+	}
+
+	var stored User
+
+	if err := primary.Clauses(dbresolver.Write).First(&stored, "name = ?", "written").Error; err != nil {
+		t.Fatalf("got %v, want the user written through the read only repository on the primary", err)
+	}
+
+	if err := replica.First(&User{}, "name = ?", "written").Error; err == nil {
+		t.Fatal("got the user written to the replica, want it on the primary only")
+	}
+}
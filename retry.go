@@ -0,0 +1,186 @@
+package regorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// transientMessages are the error messages of the transient errors across databases
+var transientMessages = []string{
+	"deadlock",
+	"lock wait timeout",
+	"could not serialize access",
+	"database is locked",
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"bad connection",
+}
+
+// nonIdempotentOperations are the operations applying twice when retried after an error leaving unknown
+// whether they were applied, like a connection reset after the database committed them
+var nonIdempotentOperations = map[string]bool{"Increment": true, "Decrement": true, "Exec": true, "RunInTransaction": true}
+
+// insertOperations are the operations inserting records, they aren't idempotent either: retried after the
+// database committed them, they insert duplicates or fail on the record they inserted. Only their statement
+// is retried, see retried, so the models are stamped and their hooks run once.
+var insertOperations = map[string]bool{
+	"Create":              true,
+	"CreateOmit":          true,
+	"CreateReturning":     true,
+	"BatchCreate":         true,
+	"BatchCreateInChunks": true,
+	"FirstOrCreate":       true,
+	"Upsert":              true,
+	"UpsertReturning":     true,
+	"BatchUpsert":         true,
+	"CreateIgnore":        true,
+}
+
+// WithRetry returns a copy of the repository retrying its write operations failing with a transient error
+// like a deadlock or a connection reset, up to maxAttempts attempts in total. It waits backoff before the
+// second attempt and doubles the wait after each attempt. Writes running in a transaction aren't retried,
+// RunInTransaction retries the whole transaction instead. The inserts like Create, BatchCreate or Upsert,
+// Increment, Decrement, Exec and RunInTransaction aren't idempotent, they are only retried on the errors
+// ensuring they weren't applied: driver.ErrBadConn, serialization failures and deadlocks (SQLSTATE 40001 and 40P01).
+// Only the statement of an insert is retried, its models are stamped and its hooks run once. The original repository is left untouched:
+//
+//	repo.WithRetry(3, 50*time.Millisecond).Create(ctx, &order)
+func (r *Repository[T]) WithRetry(maxAttempts int, backoff time.Duration) IRepository[T] {
+	clone := *r
+	clone.maxAttempts = maxAttempts
+	clone.backoff = backoff
+
+	return &clone
+}
+
+// SetRetryable sets the func reporting whether an error is transient so the write failing with it is retried,
+// nil resets it to IsTransientError. The operations which aren't idempotent are still only retried on the errors
+// ensuring they weren't applied, see WithRetry.
+func (r *Repository[T]) SetRetryable(retryable func(err error) bool) {
+	r.retryable = retryable
+}
+
+// IsTransientError reports whether err is a transient error worth retrying, like a deadlock, a serialization
+// failure or a lost connection. Constraint violations and context errors are never transient.
+func IsTransientError(err error) bool {
+	if err == nil ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, gorm.ErrDuplicatedKey) || errors.Is(err, gorm.ErrForeignKeyViolated) ||
+		errors.Is(err, gorm.ErrCheckConstraintViolated) {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var sqlState interface{ SQLState() string }
+
+	if errors.As(err, &sqlState) {
+		code := sqlState.SQLState()
+
+		// serialization failure, deadlock and connection exceptions
+		if code == "40001" || code == "40P01" || strings.HasPrefix(code, "08") {
+			return true
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+
+	for _, transient := range transientMessages {
+		if strings.Contains(message, transient) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnapplied reports whether err ensures the statement failing with it wasn't applied:
+// a connection the driver reports bad before sending the statement, or a transaction
+// rolled back by a serialization failure or a deadlock
+func isUnapplied(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var sqlState interface{ SQLState() string }
+
+	if errors.As(err, &sqlState) {
+		code := sqlState.SQLState()
+
+		return code == "40001" || code == "40P01"
+	}
+
+	return false
+}
+
+// attempt runs fn for the operation op, retrying it when op is a write failing with a retryable error.
+// The inserts retry their statement instead, see retried.
+func (r *Repository[T]) attempt(ctx context.Context, op string, current *operation, fn func(ctx context.Context) (int64, error)) (int64, error) {
+	rows, err := fn(ctx)
+
+	if insertOperations[op] {
+		return rows, err
+	}
+
+	return r.retry(ctx, op, rows, err, func() (int64, error) {
+		current.rows = 0
+
+		return fn(ctx)
+	})
+}
+
+// retried returns the insert statement stmt of the operation running in ctx retried like attempt,
+// the models are prepared and their hooks run around it once whatever the number of attempts
+func (r *Repository[T]) retried(ctx context.Context, stmt func() (int64, error)) func() (int64, error) {
+	return func() (int64, error) {
+		rows, err := stmt()
+
+		if current, ok := ctx.Value(operationKey{}).(*operation); ok {
+			return r.retry(ctx, current.name, rows, err, stmt)
+		}
+
+		return rows, err
+	}
+}
+
+// retry runs fn again while the write op fails with a retryable error, rows and err are the outcome of the first attempt
+func (r *Repository[T]) retry(ctx context.Context, op string, rows int64, err error, fn func() (int64, error)) (int64, error) {
+	if r.maxAttempts <= 1 || !writeOperations[op] || r.inTransaction() {
+		return rows, err
+	}
+
+	retryable := r.retryable
+
+	if retryable == nil {
+		retryable = IsTransientError
+	}
+
+	if nonIdempotentOperations[op] || insertOperations[op] {
+		transient := retryable
+		retryable = func(err error) bool { return transient(err) && isUnapplied(err) }
+	}
+
+	wait := r.backoff
+
+	for attempt := 1; attempt < r.maxAttempts && err != nil && retryable(err); attempt++ {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return rows, err
+		}
+
+		wait *= 2
+		rows, err = fn()
+	}
+
+	return rows, err
+}
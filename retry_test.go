@@ -0,0 +1,197 @@
+package regorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sqlStateError is a driver error carrying a SQLSTATE code
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// failer fails the create and update statements of a database with queued errors
+type failer struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+}
+
+// failing registers a failer on db failing the next statements with errs
+func failing(t *testing.T, db *gorm.DB, errs ...error) *failer {
+	t.Helper()
+
+	f := &failer{errs: errs}
+	fn := func(tx *gorm.DB) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.attempts++
+
+		if len(f.errs) > 0 {
+			_ = tx.AddError(f.errs[0])
+			f.errs = f.errs[1:]
+		}
+	}
+
+	if err := db.Callback().Create().Before("gorm:create").Register("test:fail", fn); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Callback().Update().Before("gorm:update").Register("test:fail", fn); err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	f := failing(t, db, sqlStateError("40P01"), driver.ErrBadConn)
+	repo := InitRepository[User](db).WithRetry(3, time.Millisecond)
+
+	if _, err := repo.Create(ctx, &User{Name: "alice"}); err != nil || f.attempts != 3 {
+		t.Fatalf("got %v after %d attempts, want created after 3", err, f.attempts)
+	}
+
+	f.attempts, f.errs = 0, []error{gorm.ErrDuplicatedKey}
+
+	if _, err := repo.Create(ctx, &User{Name: "bob"}); !errors.Is(err, gorm.ErrDuplicatedKey) || f.attempts != 1 {
+		t.Fatalf("got %v after %d attempts, want a constraint violation not retried", err, f.attempts)
+	}
+
+	f.attempts, f.errs = 0, []error{driver.ErrBadConn, driver.ErrBadConn, driver.ErrBadConn, driver.ErrBadConn}
+
+	if _, err := repo.Create(ctx, &User{Name: "carol"}); !errors.Is(err, driver.ErrBadConn) || f.attempts != 3 {
+		t.Fatalf("got %v after %d attempts, want to give up after 3", err, f.attempts)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, "name = ?", "carol"); err != nil || user.ID != 0 {
+		t.Fatalf("got %+v, %v, want carol never created", user, err)
+	}
+}
+
+func TestWithRetryNonIdempotent(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	users := seed(t, InitRepository[User](db), "alice")
+	f := failing(t, db, errors.New("connection reset by peer"))
+	repo := InitRepository[User](db).WithRetry(3, time.Millisecond)
+
+	if _, err := repo.Increment(ctx, users[0].ID, "age", 1); err == nil || f.attempts != 1 {
+		t.Fatalf("got %v after %d attempts, want a connection reset not retried", err, f.attempts)
+	}
+
+	f.attempts, f.errs = 0, []error{sqlStateError("40001"), sqlStateError("40P01")}
+
+	if _, err := repo.Increment(ctx, users[0].ID, "age", 1); err != nil || f.attempts != 3 {
+		t.Fatalf("got %v after %d attempts, want serialization failures retried", err, f.attempts)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, users[0].ID); err != nil || user.Age != users[0].Age+1 {
+		t.Fatalf("got %+v, %v, want age incremented once", user, err)
+	}
+}
+
+func TestWithRetryInsert(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, &Hooked{})
+	f := failing(t, db)
+	repo := InitRepository[Hooked](db).WithRetry(3, time.Millisecond)
+
+	inserts := map[string]func(model *Hooked) error{
+		"Create": func(model *Hooked) error {
+			_, err := repo.Create(ctx, model)
+			return err
+		},
+		"CreateOmit": func(model *Hooked) error {
+			_, err := repo.CreateOmit(ctx, model)
+			return err
+		},
+		"BatchCreate": func(model *Hooked) error {
+			_, err := repo.BatchCreate(ctx, []*Hooked{model})
+			return err
+		},
+		"BatchCreateInChunks": func(model *Hooked) error {
+			_, err := repo.BatchCreateInChunks(ctx, []*Hooked{model}, 10)
+			return err
+		},
+		"FirstOrCreate": func(model *Hooked) error {
+			_, _, err := repo.FirstOrCreate(ctx, model, Hooked{Code: model.Code})
+			return err
+		},
+		"Upsert": func(model *Hooked) error {
+			return repo.Upsert(ctx, model, []string{"code"}, nil)
+		},
+		"BatchUpsert": func(model *Hooked) error {
+			_, err := repo.BatchUpsert(ctx, []*Hooked{model}, []string{"code"}, nil)
+			return err
+		},
+		"CreateIgnore": func(model *Hooked) error {
+			_, err := repo.CreateIgnore(ctx, []*Hooked{model})
+			return err
+		},
+	}
+
+	for name, insert := range inserts {
+		f.attempts, f.errs = 0, []error{errors.New("connection reset by peer")}
+
+		// the insert may have been committed before the connection was lost
+		if err := insert(&Hooked{Code: name}); err == nil || f.attempts != 1 {
+			t.Errorf("%s: got %v after %d attempts, want a connection reset not retried", name, err, f.attempts)
+		}
+
+		f.attempts, f.errs = 0, []error{driver.ErrBadConn, sqlStateError("40001")}
+
+		if err := insert(&Hooked{Code: name}); err != nil || f.attempts != 3 {
+			t.Errorf("%s: got %v after %d attempts, want the errors ensuring no insert retried", name, err, f.attempts)
+		}
+	}
+
+	f.attempts, f.errs = 0, []error{driver.ErrBadConn, driver.ErrBadConn}
+	model := &Hooked{Code: "hooked"}
+
+	if _, err := repo.Create(ctx, model); err != nil || f.attempts != 3 {
+		t.Fatalf("got %v after %d attempts, want created after 3", err, f.attempts)
+	}
+
+	if !reflect.DeepEqual(model.calls, []string{"BeforeCreate", "AfterCreate"}) {
+		t.Fatalf("got %v, want the hooks run once", model.calls)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{driver.ErrBadConn, true},
+		{sqlStateError("40001"), true},
+		{sqlStateError("08006"), true},
+		{sqlStateError("23505"), false},
+		{errors.New("Error 1213: Deadlock found when trying to get lock"), true},
+		{errors.New("database is locked"), true},
+		{gorm.ErrDuplicatedKey, false},
+		{context.DeadlineExceeded, false},
+	}
+
+	for _, test := range tests {
+		if got := IsTransientError(test.err); got != test.want {
+			t.Errorf("IsTransientError(%v): got %t, want %t", test.err, got, test.want)
+		}
+	}
+}
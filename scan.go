@@ -0,0 +1,91 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ScanInto runs a query over the repository model shaped by opts and scans the result into dest,
+// dest can be any struct or slice of structs like a report DTO:
+//
+//	var report []struct {
+//		Status string
+//		Total  int64
+//	}
+//	repo.ScanInto(ctx, &report, regorm.Select("status", "COUNT(*) AS total"), func(db *gorm.DB) *gorm.DB {
+//		return db.Group("status")
+//	})
+func (r *Repository[T]) ScanInto(ctx context.Context, dest interface{}, opts ...QueryOption) error {
+	return r.run(ctx, "ScanInto", func(ctx context.Context) (int64, error) {
+		db := r.readScoped(r.db(ctx)).Model(new(T))
+
+		for _, opt := range opts {
+			db = opt(db)
+		}
+
+		res := db.Scan(dest)
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// FindMaps scans the records shaped by opts into dest, a map of column to value per record,
+// for ad-hoc selections without a struct:
+//
+//	var rows []map[string]interface{}
+//	repo.FindMaps(ctx, &rows, regorm.Select("name", "email"), regorm.Where("active = ?", true))
+func (r *Repository[T]) FindMaps(ctx context.Context, dest *[]map[string]interface{}, opts ...QueryOption) error {
+	return r.run(ctx, "FindMaps", func(ctx context.Context) (int64, error) {
+		return 0, r.ScanInto(ctx, dest, opts...)
+	})
+}
+
+// SubQuery builds a query over the repository model shaped by opts without running it,
+// to be used as a subquery of another query like WhereIn
+func (r *Repository[T]) SubQuery(opts ...QueryOption) *gorm.DB {
+	db := r.scoped(r.Database).Scopes(r.scopes...).Model(new(T))
+
+	for _, opt := range opts {
+		db = opt(db)
+	}
+
+	return db
+}
+
+// ExplainSQL returns the SQL op would run over the repository model with its args bound, without running it,
+// the scopes of the repository included:
+//
+//	sql := repo.ExplainSQL(func(db *gorm.DB) *gorm.DB {
+//		return db.Where("age > ?", 18).Find(&[]User{})
+//	})
+//	// SELECT * FROM "users" WHERE age > 18 AND "users"."deleted_at" IS NULL
+func (r *Repository[T]) ExplainSQL(op func(db *gorm.DB) *gorm.DB) string {
+	return r.Database.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return op(r.readScoped(r.scoped(tx)).Model(new(T)))
+	})
+}
+
+// Raw runs a raw SQL query and scans the result into dest, use it for
+// the queries GORM query builder can't express:
+//
+//	var users []User
+//	repo.Raw(ctx, &users, "SELECT * FROM users WHERE age > ?", 18)
+func (r *Repository[T]) Raw(ctx context.Context, dest interface{}, sql string, args ...interface{}) error {
+	return r.run(ctx, "Raw", func(ctx context.Context) (int64, error) {
+		res := r.db(ctx).Raw(sql, args...).Scan(dest)
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// Exec runs a raw SQL statement and returns rows affected
+//
+//	repo.Exec(ctx, "UPDATE users SET active = ? WHERE last_login < ?", false, deadline)
+func (r *Repository[T]) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	return r.runRows(ctx, "Exec", func(ctx context.Context) (int64, error) {
+		res := r.db(ctx).Exec(sql, args...)
+
+		return res.RowsAffected, res.Error
+	})
+}
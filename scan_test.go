@@ -0,0 +1,120 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestScanInto(t *testing.T) {
+	repo := InitRepository[Customer](openDB(t, &Customer{}, &Purchase{}, &Item{}))
+	seedCustomers(t, repo)
+
+	var report []struct {
+		Name   string
+		Orders int64
+	}
+
+	err := repo.ScanInto(context.Background(), &report,
+		Select("customers.name", "COUNT(purchases.id) AS orders"),
+		func(db *gorm.DB) *gorm.DB {
+			return db.Joins("LEFT JOIN purchases ON purchases.customer_id = customers.id").
+				Group("customers.id, customers.name").
+				Order("customers.id")
+		},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report) != 2 || report[0].Name != "alice" || report[0].Orders != 2 || report[1].Name != "bob" || report[1].Orders != 0 {
+		t.Fatalf("got %+v, want the order count per customer", report)
+	}
+}
+
+func TestFindMaps(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol")
+
+	var rows []map[string]interface{}
+
+	if err := repo.FindMaps(ctx, &rows, Select("name", "email"), Where("age > ?", 20), Order("id")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 2 || rows[0]["name"] != "bob" || rows[0]["email"] != "bob@example.com" || rows[1]["name"] != "carol" {
+		t.Fatalf("got %v, want bob and carol", rows)
+	}
+
+	for _, row := range rows {
+		if len(row) != 2 {
+			t.Fatalf("got %v, want only name and email", row)
+		}
+	}
+}
+
+func TestRaw(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	seed(t, repo, "alice", "bob", "carol")
+
+	var users []User
+
+	if err := repo.Raw(context.Background(), &users, "SELECT * FROM users WHERE age > ? AND name <> ? ORDER BY id", 20, "carol"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 1 || users[0].Name != "bob" || users[0].Email != "bob@example.com" {
+		t.Fatalf("got %+v, want bob", users)
+	}
+}
+
+func TestExec(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol")
+
+	n, err := repo.Exec(ctx, "UPDATE users SET age = ? WHERE age >= ?", 99, 21)
+
+	if err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2 rows affected", n, err)
+	}
+
+	if count, err := repo.Count(ctx, "age = ?", 99); err != nil || count != 2 {
+		t.Fatalf("got %d, %v, want 2 records updated", count, err)
+	}
+}
+
+func TestExplainSQL(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+
+	sql := repo.ExplainSQL(func(db *gorm.DB) *gorm.DB {
+		return db.Where("age > ?", 18).Find(&[]User{})
+	})
+
+	if want := "SELECT * FROM `users` WHERE age > 18 AND `users`.`deleted_at` IS NULL"; sql != want {
+		t.Fatalf("got %q, want %q", sql, want)
+	}
+
+	sql = repo.ExplainSQL(func(db *gorm.DB) *gorm.DB {
+		return db.First(&User{}, 1)
+	})
+
+	if want := "SELECT * FROM `users` WHERE `users`.`id` = 1 AND `users`.`deleted_at` IS NULL ORDER BY `users`.`id` LIMIT 1"; sql != want {
+		t.Fatalf("got %q, want %q", sql, want)
+	}
+
+	adults := repo.WithScope(func(db *gorm.DB) *gorm.DB {
+		return db.Where("age > ?", 20)
+	})
+
+	sql = adults.ExplainSQL(func(db *gorm.DB) *gorm.DB {
+		return db.Where("name = ?", "bob").Find(&[]User{})
+	})
+
+	if want := "SELECT * FROM `users` WHERE name = \"bob\" AND age > 20 AND `users`.`deleted_at` IS NULL"; sql != want {
+		t.Fatalf("got %q, want %q", sql, want)
+	}
+}
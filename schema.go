@@ -0,0 +1,238 @@
+package regorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// column validates name against the model schema and returns its database column name.
+// name can be either the column name or the struct field name.
+func (r *Repository[T]) column(name string) (string, error) {
+	return parseColumn(r.Database, new(T), name)
+}
+
+// equals builds a column = value condition, column is validated against the model schema
+func (r *Repository[T]) equals(column string, value interface{}) (clause.Expression, error) {
+	name, err := r.column(column)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: name}, Value: value}, nil
+}
+
+// primaryKey returns the primary key column of the model,
+// returns ErrInvalidPrimaryKey if the model has no single primary key
+func (r *Repository[T]) primaryKey() (string, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return "", err
+	}
+
+	if s.PrioritizedPrimaryField == nil || s.PrioritizedPrimaryField.DBName == "" {
+		return "", ErrInvalidPrimaryKey
+	}
+
+	return s.PrioritizedPrimaryField.DBName, nil
+}
+
+// versionField returns the integer Version field of the schema used for optimistic locking, nil if there is none
+func versionField(s *schema.Schema) *schema.Field {
+	field := s.LookUpField("Version")
+
+	if field == nil || field.DBName == "" {
+		return nil
+	}
+
+	switch field.FieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field
+	}
+
+	return nil
+}
+
+// primaryKeyZero reports whether any primary key of value is zero, so value isn't stored yet
+func primaryKeyZero(ctx context.Context, s *schema.Schema, value reflect.Value) bool {
+	if len(s.PrimaryFields) == 0 {
+		return true
+	}
+
+	for _, field := range s.PrimaryFields {
+		if _, zero := field.ValueOf(ctx, value); zero {
+			return true
+		}
+	}
+
+	return false
+}
+
+// primaryKeyCondition builds the condition matching every primary key of the schema s to its value in value,
+// zero values included
+func primaryKeyCondition(ctx context.Context, s *schema.Schema, value reflect.Value) clause.Expression {
+	conds := make([]clause.Expression, 0, len(s.PrimaryFields))
+
+	for _, field := range s.PrimaryFields {
+		fieldValue, _ := field.ValueOf(ctx, value)
+		conds = append(conds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: fieldValue})
+	}
+
+	return clause.And(conds...)
+}
+
+// keyed restricts db to the record of model when the model has a composite primary key, as GORM matches
+// only the non-zero primary keys of model and would match many records when one of them is zero
+func (r *Repository[T]) keyed(ctx context.Context, db *gorm.DB, model *T) (*gorm.DB, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.PrimaryFields) < 2 {
+		return db, nil
+	}
+
+	return db.Where(primaryKeyCondition(ctx, s, reflect.ValueOf(model).Elem())), nil
+}
+
+// parseSchema parses the schema of model, parsed schemas are cached by GORM
+func parseSchema(db *gorm.DB, model interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	return stmt.Schema, nil
+}
+
+// byID builds a primary key = id condition
+func (r *Repository[T]) byID(id interface{}) (clause.Expression, error) {
+	column, err := r.primaryKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: id}, nil
+}
+
+// byCompositeKey builds the condition matching every primary key of the model to its value in keys
+func (r *Repository[T]) byCompositeKey(keys map[string]interface{}) (clause.Expression, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.PrimaryFields) == 0 || len(keys) != len(s.PrimaryFields) {
+		return nil, ErrInvalidCompositeKey
+	}
+
+	values := make(map[string]interface{}, len(keys))
+
+	for name, value := range keys {
+		field := s.LookUpField(name)
+
+		if field == nil || !field.PrimaryKey {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCompositeKey, name)
+		}
+
+		values[field.DBName] = value
+	}
+
+	conds := make([]clause.Expression, 0, len(s.PrimaryFields))
+
+	for _, field := range s.PrimaryFields {
+		value, ok := values[field.DBName]
+
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %q", ErrInvalidCompositeKey, field.DBName)
+		}
+
+		conds = append(conds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+	}
+
+	return clause.And(conds...), nil
+}
+
+// returning builds the RETURNING clause of columns along with the primary keys,
+// columns are validated against the model schema
+func (r *Repository[T]) returning(columns []string) (clause.Returning, error) {
+	returning := clause.Returning{}
+
+	if len(columns) == 0 {
+		return returning, nil
+	}
+
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return returning, err
+	}
+
+	names := make(map[string]bool, len(columns)+len(s.PrimaryFields))
+
+	for _, column := range columns {
+		field, err := parseField(s, column)
+
+		if err != nil {
+			return returning, err
+		}
+
+		names[field.DBName] = true
+		returning.Columns = append(returning.Columns, clause.Column{Name: field.DBName})
+	}
+
+	for _, field := range s.PrimaryFields {
+		if !names[field.DBName] {
+			returning.Columns = append(returning.Columns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	return returning, nil
+}
+
+// parseColumn validates name against the schema of model and returns its database column name
+func parseColumn(db *gorm.DB, model interface{}, name string) (string, error) {
+	if model == nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidColumn, name)
+	}
+
+	s, err := parseSchema(db, model)
+
+	if err != nil {
+		return "", err
+	}
+
+	field, err := parseField(s, name)
+
+	if err != nil {
+		return "", err
+	}
+
+	return field.DBName, nil
+}
+
+// parseField validates name against the schema s and returns its field,
+// name can be either the column name or the struct field name
+func parseField(s *schema.Schema, name string) (*schema.Field, error) {
+	field := s.LookUpField(name)
+
+	if field == nil || field.DBName == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidColumn, name)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,53 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type UserRole struct {
+	UserID uint `gorm:"primaryKey;autoIncrement:false"`
+	RoleID uint `gorm:"primaryKey;autoIncrement:false"`
+	Note   string
+}
+
+func (UserRole) TableName() string { return "user_roles" }
+
+func TestCompositeKey(t *testing.T) {
+	repo := InitRepository[UserRole](openDB(t, &UserRole{}))
+	ctx := context.Background()
+	roles := []*UserRole{{UserID: 1, RoleID: 1, Note: "a"}, {UserID: 1, RoleID: 2, Note: "b"}, {UserID: 2, RoleID: 1, Note: "c"}}
+
+	if _, err := repo.BatchCreate(ctx, roles); err != nil {
+		t.Fatal(err)
+	}
+
+	var role UserRole
+
+	if err := repo.FindByCompositeKey(ctx, &role, map[string]interface{}{"user_id": 1, "RoleID": 2}); err != nil || role.Note != "b" {
+		t.Fatalf("got %+v, %v, want b", role, err)
+	}
+
+	for _, keys := range []map[string]interface{}{{"user_id": 1}, {"user_id": 1, "role_id": 2, "note": "b"}, {"user_id": 1, "missing": 2}} {
+		if err := repo.FindByCompositeKey(ctx, &role, keys); !errors.Is(err, ErrInvalidCompositeKey) {
+			t.Fatalf("%v: got %v, want ErrInvalidCompositeKey", keys, err)
+		}
+	}
+
+	roles[1].Note = "changed"
+
+	if err := repo.Update(ctx, roles[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.Delete(ctx, &UserRole{UserID: 1, RoleID: 1}); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want only (1, 1) deleted", n, err)
+	}
+
+	var left []UserRole
+
+	if err := repo.Find(ctx, &left); err != nil || len(left) != 2 || left[0].Note != "changed" || left[1].Note != "c" {
+		t.Fatalf("got %+v, %v, want (1, 2) updated and (2, 1) untouched", left, err)
+	}
+}
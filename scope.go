@@ -0,0 +1,62 @@
+package regorm
+
+import "gorm.io/gorm"
+
+// WithScope returns a copy of the repository applying scope to every read, like First, FirstForUpdate, Find,
+// Count, SubQuery or the lookup of FirstOrCreate, writes aren't scoped. Scopes compose, each WithScope adds to the scopes of the repository:
+//
+//	active := repo.WithScope(func(db *gorm.DB) *gorm.DB {
+//		return db.Where("archived = ?", false)
+//	})
+//	active.Find(ctx, &users) // SELECT * FROM users WHERE archived = false
+//
+// The original repository is left untouched.
+func (r *Repository[T]) WithScope(scope func(*gorm.DB) *gorm.DB) IRepository[T] {
+	clone := *r
+	clone.scopes = append(append([]func(*gorm.DB) *gorm.DB{}, r.scopes...), scope)
+
+	return &clone
+}
+
+// MaskColumns makes the reads of repository omit columns, like password hashes or tokens,
+// so they are left zero valued unless the query selects them explicitly with Select.
+// Update, UpdateOmit and UpdateExisting don't write the masked columns of existing records
+// either, so saving a record read without them leaves them untouched. Update them with UpdateColumns.
+func (r *Repository[T]) MaskColumns(columns ...string) {
+	r.maskedColumns = columns
+}
+
+// readScoped applies the scopes of WithScope and the masked columns to db, the queries reading the model table are
+// built on it, including the lookups of writes like FirstOrCreate
+func (r *Repository[T]) readScoped(db *gorm.DB) *gorm.DB {
+	if len(r.scopes) == 0 && len(r.maskedColumns) == 0 {
+		return db
+	}
+
+	db = db.Scopes(r.scopes...)
+
+	if len(r.maskedColumns) > 0 {
+		// after the scopes, which may select the masked columns
+		db = db.Scopes(r.mask)
+	}
+
+	return db
+}
+
+// mask omits the masked columns from the query unless it selects columns explicitly
+func (r *Repository[T]) mask(db *gorm.DB) *gorm.DB {
+	if len(db.Statement.Selects) > 0 {
+		return db
+	}
+
+	return db.Omit(r.maskedColumns...)
+}
+
+// omitMasked omits the masked columns from the update run on db, along with the columns db omits already
+func (r *Repository[T]) omitMasked(db *gorm.DB) *gorm.DB {
+	if len(r.maskedColumns) == 0 {
+		return db
+	}
+
+	return db.Omit(append(append([]string{}, db.Statement.Omits...), r.maskedColumns...)...)
+}
@@ -0,0 +1,177 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestWithScope(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol")
+
+	adults := repo.WithScope(func(db *gorm.DB) *gorm.DB {
+		return db.Where("age > ?", 20)
+	})
+	scoped := adults.WithScope(func(db *gorm.DB) *gorm.DB {
+		return db.Where("name <> ?", "carol")
+	})
+
+	var users []User
+
+	if err := scoped.Find(ctx, &users); err != nil || len(users) != 1 || users[0].Name != "bob" {
+		t.Fatalf("got %+v, %v, want the scopes composed", users, err)
+	}
+
+	if err := adults.Find(ctx, &users); err != nil || len(users) != 2 {
+		t.Fatalf("got %+v, %v, want bob and carol", users, err)
+	}
+
+	if err := repo.Find(ctx, &users); err != nil || len(users) != 3 {
+		t.Fatalf("got %+v, %v, want the original repository unscoped", users, err)
+	}
+
+	if count, err := scoped.Count(ctx); err != nil || count != 1 {
+		t.Fatalf("Count: got %d, %v, want 1", count, err)
+	}
+
+	var user User
+
+	if err := scoped.First(ctx, &user); err != nil || user.Name != "bob" {
+		t.Fatalf("First: got %+v, %v, want bob", user, err)
+	}
+
+	user = User{}
+
+	if err := scoped.FirstForUpdate(ctx, &user); err != nil || user.Name != "bob" {
+		t.Fatalf("FirstForUpdate: got %+v, %v, want bob", user, err)
+	}
+
+	user = User{}
+
+	if err := scoped.FirstForShare(ctx, &user); err != nil || user.Name != "bob" {
+		t.Fatalf("FirstForShare: got %+v, %v, want bob", user, err)
+	}
+
+	page, err := scoped.FindPaginated(ctx, 1, 10)
+
+	if err != nil || page.Total != 1 || len(page.Items) != 1 {
+		t.Fatalf("FindPaginated: got %+v, %v, want bob", page, err)
+	}
+
+	// alice is out of the scope, so FirstOrCreate creates another one
+	created, ok, err := adults.FirstOrCreate(ctx, &User{Name: "alice", Age: 30}, map[string]interface{}{"name": "alice"})
+
+	if err != nil || !ok || created.Age != 30 {
+		t.Fatalf("FirstOrCreate: got %+v, %t, %v, want alice created", created, ok, err)
+	}
+
+	if count, err := repo.Count(ctx, map[string]interface{}{"name": "alice"}); err != nil || count != 2 {
+		t.Fatalf("got %d, %v, want 2 alices", count, err)
+	}
+
+	if _, err := adults.UpdateColumns(ctx, map[string]interface{}{"name": "alice", "age": 20}, map[string]interface{}{"email": "new@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	user = User{}
+
+	if err := repo.First(ctx, &user, 1); err != nil || user.Email != "new@example.com" {
+		t.Fatalf("got %+v, %v, want writes unscoped", user, err)
+	}
+}
+
+func TestMaskColumns(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+	seed(t, repo, "alice")
+	repo.MaskColumns("email")
+
+	stored := func() User {
+		t.Helper()
+
+		var user User
+
+		if err := db.First(&user, 1).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		return user
+	}
+
+	reads := map[string]func(user *User) error{
+		"First":          func(user *User) error { return repo.First(ctx, user, 1) },
+		"FirstForUpdate": func(user *User) error { return repo.FirstForUpdate(ctx, user, 1) },
+		"FirstForShare":  func(user *User) error { return repo.FirstForShare(ctx, user, 1) },
+		"FirstOrCreate": func(user *User) error {
+			_, _, err := repo.FirstOrCreate(ctx, user, map[string]interface{}{"name": "alice"})
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		var user User
+
+		if err := read(&user); err != nil || user.Name != "alice" || user.Email != "" {
+			t.Errorf("%s: got %+v, %v, want alice without her email", name, user, err)
+		}
+	}
+
+	var selected User
+
+	if err := repo.First(ctx, &selected, Select("name", "email")); err != nil || selected.Email != "alice@example.com" {
+		t.Fatalf("got %+v, %v, want the selected email", selected, err)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	user.Age = 30
+
+	if err := repo.Update(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stored(); got.Age != 30 || got.Email != "alice@example.com" {
+		t.Fatalf("Update: got %+v, want the email untouched", got)
+	}
+
+	user.Age, user.Name = 31, "alicia"
+
+	if err := repo.UpdateOmit(ctx, &user, "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stored(); got.Age != 31 || got.Name != "alice" || got.Email != "alice@example.com" {
+		t.Fatalf("UpdateOmit: got %+v, want the name and email untouched", got)
+	}
+
+	user.Age = 32
+
+	if _, err := repo.UpdateExisting(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stored(); got.Age != 32 || got.Email != "alice@example.com" {
+		t.Fatalf("UpdateExisting: got %+v, want the email untouched", got)
+	}
+
+	bob := User{Name: "bob", Email: "bob@example.com"}
+
+	if err := repo.Update(ctx, &bob); err != nil {
+		t.Fatal(err)
+	}
+
+	var inserted User
+
+	if err := db.First(&inserted, bob.ID).Error; err != nil || inserted.Email != "bob@example.com" {
+		t.Fatalf("got %+v, %v, want the email of an inserted record written", inserted, err)
+	}
+}
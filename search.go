@@ -0,0 +1,74 @@
+package regorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes the LIKE wildcards of search terms, ! is the escape character as it needs
+// no escaping in the string literals of any dialect
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Search finds the records one of columns of contains term case insensitively, along with the
+// records matching given conditions. Wildcards in term like % are matched literally:
+//
+//	repo.Search(ctx, &users, []string{"name", "email"}, "john", regorm.Where("active = ?", true))
+//
+// ILIKE is used on Postgres, LOWER(column) LIKE LOWER(term) on the other databases. Columns are validated
+// against the model schema and ErrInvalidColumn is returned if one doesn't exist or columns is empty.
+// All the records matching given conditions are found when term is empty.
+func (r *Repository[T]) Search(ctx context.Context, models *[]T, columns []string, term string, conds ...interface{}) error {
+	return r.run(ctx, "Search", func(ctx context.Context) (int64, error) {
+		if len(columns) == 0 {
+			return 0, fmt.Errorf("%w: no search column", ErrInvalidColumn)
+		}
+
+		db := r.query(ctx, conds)
+
+		if term != "" {
+			cond, err := r.search(db, columns, term)
+
+			if err != nil {
+				return 0, err
+			}
+
+			db = db.Where(cond)
+		}
+
+		res := db.Find(models)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, models)
+	})
+}
+
+// search builds the condition matching the records one of columns of contains term case insensitively
+func (r *Repository[T]) search(db *gorm.DB, columns []string, term string) (clause.Expression, error) {
+	pattern := "%" + likeEscaper.Replace(term) + "%"
+	like := "LOWER(?) LIKE LOWER(?) ESCAPE '!'"
+
+	if db.Dialector.Name() == "postgres" {
+		like = "? ILIKE ? ESCAPE '!'"
+	}
+
+	exprs := make([]clause.Expression, 0, len(columns))
+
+	for _, column := range columns {
+		name, err := r.column(column)
+
+		if err != nil {
+			return nil, err
+		}
+
+		exprs = append(exprs, clause.Expr{SQL: like, Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: name}, pattern}})
+	}
+
+	return clause.Or(exprs...), nil
+}
@@ -0,0 +1,55 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "Johnny", "bob", "Ann_Marie")
+
+	if _, err := repo.Create(ctx, &User{Name: "carol", Email: "JOHN@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var users []User
+
+	if err := repo.Search(ctx, &users, []string{"name", "email"}, "john"); err != nil || len(users) != 2 {
+		t.Fatalf("got %+v, %v, want Johnny and carol", users, err)
+	}
+
+	if users[0].Name != "Johnny" || users[1].Name != "carol" {
+		t.Fatalf("got %+v, want the partial matches of both columns", users)
+	}
+
+	if err := repo.Search(ctx, &users, []string{"name", "email"}, "john", Where("name = ?", "carol")); err != nil || len(users) != 1 {
+		t.Fatalf("got %+v, %v, want carol only", users, err)
+	}
+
+	if err := repo.Search(ctx, &users, []string{"name"}, "n_y"); err != nil || len(users) != 0 {
+		t.Fatalf("got %+v, %v, want _ matched literally", users, err)
+	}
+
+	if err := repo.Search(ctx, &users, []string{"name"}, "n_M"); err != nil || len(users) != 1 {
+		t.Fatalf("got %+v, %v, want Ann_Marie", users, err)
+	}
+
+	if err := repo.Search(ctx, &users, []string{"password"}, "john"); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+
+	db := openDialect(t, "postgres")
+	rec := record(t, db)
+
+	if err := InitRepository[User](db).Search(ctx, &users, []string{"name", "email"}, "john"); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); !strings.Contains(sql, "(`users`.`name` ILIKE ? ESCAPE '!' OR `users`.`email` ILIKE ? ESCAPE '!')") {
+		t.Fatalf("got %q, want ILIKE on Postgres", sql)
+	}
+}
@@ -0,0 +1,154 @@
+package regorm
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// trashedScopeKey marks the statements the soft delete condition is already applied to
+const trashedScopeKey = "regorm:trashed_scope"
+
+// SetSoftDeleteColumn sets the column repository soft deletes with, for legacy schemas not using gorm.DeletedAt.
+// A boolean column like is_deleted is set to true on delete and false on restore,
+// any other column like removed_at is stamped with the current time on delete and set to null on restore.
+// Queries of repository skip the soft deleted records unless they are made WithTrashed.
+// An empty column resets repository to the model's gorm.DeletedAt field.
+func (r *Repository[T]) SetSoftDeleteColumn(column string) {
+	r.softDeleteColumn = column
+}
+
+// softDeleteField returns the soft delete field of the model, the column set with SetSoftDeleteColumn
+// or else the gorm.DeletedAt field. Returns ErrNotSoftDeletable if the model has no such field.
+func (r *Repository[T]) softDeleteField() (*schema.Field, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if r.softDeleteColumn != "" {
+		field := s.LookUpField(r.softDeleteColumn)
+
+		if field == nil || field.DBName == "" {
+			return nil, ErrNotSoftDeletable
+		}
+
+		return field, nil
+	}
+
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.FieldType == deletedAtType {
+			return field, nil
+		}
+	}
+
+	return nil, ErrNotSoftDeletable
+}
+
+// scoped applies the table of WithTable and WithSchema, the tenant condition of WithTenant and the soft delete condition
+// of the column set with SetSoftDeleteColumn to the statements of db, gorm.DeletedAt fields are left to GORM
+func (r *Repository[T]) scoped(db *gorm.DB) *gorm.DB {
+	if r.tableName != "" || r.schemaName != "" {
+		db = r.scopeTable(db)
+	}
+
+	if r.tenantColumn != "" {
+		db = db.Scopes(r.scopeTenant)
+	}
+
+	if r.softDeleteColumn != "" {
+		db = db.Scopes(r.excludeTrashed)
+	}
+
+	return db
+}
+
+// excludeTrashed is the scope skipping soft deleted records, it runs when the statement executes
+// so that Unscoped queries are left untouched
+func (r *Repository[T]) excludeTrashed(db *gorm.DB) *gorm.DB {
+	if db.Statement.Unscoped {
+		return db
+	}
+
+	if _, applied := db.InstanceGet(trashedScopeKey); applied {
+		return db
+	}
+
+	field, err := r.softDeleteField()
+
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+
+	if field.FieldType == deletedAtType {
+		return db
+	}
+
+	return db.InstanceSet(trashedScopeKey, true).Where(notTrashed(field))
+}
+
+// remove deletes model with db, soft deletes by updating the column set with SetSoftDeleteColumn if any
+func (r *Repository[T]) remove(db *gorm.DB, model interface{}) *gorm.DB {
+	if r.softDeleteColumn == "" || db.Statement.Unscoped {
+		return db.Delete(model)
+	}
+
+	field, err := r.softDeleteField()
+
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+
+	if field.FieldType == deletedAtType {
+		return db.Delete(model)
+	}
+
+	var value interface{} = true
+
+	if !isFlag(field) {
+		value = r.Database.NowFunc()
+	}
+
+	return db.Model(model).UpdateColumn(field.DBName, value)
+}
+
+// restoredValue returns the value of the soft delete field of a record which isn't deleted
+func restoredValue(field *schema.Field) interface{} {
+	if isFlag(field) {
+		return false
+	}
+
+	return nil
+}
+
+// trashed builds the condition matching the records soft deleted by field
+func trashed(field *schema.Field) clause.Expression {
+	column := clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+
+	if isFlag(field) {
+		return clause.Eq{Column: column, Value: true}
+	}
+
+	return clause.Neq{Column: column, Value: nil}
+}
+
+// notTrashed builds the condition matching the records not soft deleted by field
+func notTrashed(field *schema.Field) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: restoredValue(field)}
+}
+
+// isFlag reports whether field is a boolean soft delete flag like is_deleted
+func isFlag(field *schema.Field) bool {
+	fieldType := field.FieldType
+
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Bool
+}
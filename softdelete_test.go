@@ -0,0 +1,117 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type Legacy struct {
+	ID        uint
+	Name      string
+	RemovedAt *time.Time
+}
+
+func (Legacy) TableName() string { return "legacies" }
+
+type Flagged struct {
+	ID        uint
+	Name      string
+	IsDeleted bool
+}
+
+func (Flagged) TableName() string { return "flagged" }
+
+func TestSoftDeleteTimestampColumn(t *testing.T) {
+	db := openDB(t, &Legacy{})
+	repo := InitRepository[Legacy](db)
+	repo.SetSoftDeleteColumn("removed_at")
+	ctx := context.Background()
+	a, b := &Legacy{Name: "a"}, &Legacy{Name: "b"}
+
+	if _, err := repo.BatchCreate(ctx, []*Legacy{a, b}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.Delete(ctx, a); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	var raw Legacy
+
+	if err := db.First(&raw, a.ID).Error; err != nil || raw.RemovedAt == nil {
+		t.Fatalf("got %+v, %v, want removed_at stamped", raw, err)
+	}
+
+	var found []Legacy
+
+	if err := repo.Find(ctx, &found); err != nil || len(found) != 1 || found[0].Name != "b" {
+		t.Fatalf("Find: got %+v, %v, want only b", found, err)
+	}
+
+	if err := repo.FindWithTrashed(ctx, &found); err != nil || len(found) != 2 {
+		t.Fatalf("FindWithTrashed: got %+v, %v, want both", found, err)
+	}
+
+	if err := repo.FindOnlyTrashed(ctx, &found); err != nil || len(found) != 1 || found[0].Name != "a" {
+		t.Fatalf("FindOnlyTrashed: got %+v, %v, want only a", found, err)
+	}
+
+	if n, err := repo.Restore(ctx, a); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	if err := repo.Find(ctx, &found); err != nil || len(found) != 2 {
+		t.Fatalf("got %+v, %v, want a restored", found, err)
+	}
+}
+
+func TestSoftDeleteFlagColumn(t *testing.T) {
+	db := openDB(t, &Flagged{})
+	repo := InitRepository[Flagged](db)
+	repo.SetSoftDeleteColumn("is_deleted")
+	ctx := context.Background()
+	a, b := &Flagged{Name: "a"}, &Flagged{Name: "b"}
+
+	if _, err := repo.BatchCreate(ctx, []*Flagged{a, b}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.DeleteByID(ctx, a.ID); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	var raw Flagged
+
+	if err := db.First(&raw, a.ID).Error; err != nil || !raw.IsDeleted {
+		t.Fatalf("got %+v, %v, want is_deleted set", raw, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want only b counted", n, err)
+	}
+
+	var found []Flagged
+
+	if err := repo.FindOnlyTrashed(ctx, &found); err != nil || len(found) != 1 || found[0].Name != "a" {
+		t.Fatalf("FindOnlyTrashed: got %+v, %v, want only a", found, err)
+	}
+
+	if _, err := repo.Restore(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.First(&raw, a.ID).Error; err != nil || raw.IsDeleted {
+		t.Fatalf("got %+v, %v, want is_deleted cleared", raw, err)
+	}
+
+	if n, err := repo.ForceDelete(ctx, b); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want b deleted", n, err)
+	}
+
+	var total int64
+
+	if err := db.Model(&Flagged{}).Count(&total).Error; err != nil || total != 1 {
+		t.Fatalf("got %d, %v, want b gone", total, err)
+	}
+}
@@ -0,0 +1,79 @@
+package regorm
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Specification is a reusable filter over the records of a model, specifications are passed
+// along with the conditions of read methods like query options and can be combined with And and Or:
+//
+//	type ActiveUsers struct{}
+//
+//	func (ActiveUsers) Apply(db *gorm.DB) *gorm.DB {
+//		return db.Where("active = ?", true)
+//	}
+//
+//	type CreatedAfter struct{ Time time.Time }
+//
+//	func (s CreatedAfter) Apply(db *gorm.DB) *gorm.DB {
+//		return db.Where("created_at > ?", s.Time)
+//	}
+//
+//	repo.Find(ctx, &users, regorm.And(ActiveUsers{}, CreatedAfter{lastWeek}))
+//	repo.Count(ctx, regorm.Or(ActiveUsers{}, CreatedAfter{lastWeek}))
+type Specification interface {
+	Apply(db *gorm.DB) *gorm.DB
+}
+
+// Apply applies the query option to db, so every query option is a specification too
+func (o QueryOption) Apply(db *gorm.DB) *gorm.DB {
+	return o(db)
+}
+
+// And returns a specification matching the records matching all of specs
+func And(specs ...Specification) Specification {
+	return QueryOption(func(db *gorm.DB) *gorm.DB {
+		for _, spec := range specs {
+			db = spec.Apply(db)
+		}
+
+		return db
+	})
+}
+
+// Or returns a specification matching the records matching any of specs. Only the
+// conditions of specs are combined, the rest of the query they shape like joins or
+// ordering is ignored.
+func Or(specs ...Specification) Specification {
+	return QueryOption(func(db *gorm.DB) *gorm.DB {
+		exprs := make([]clause.Expression, 0, len(specs))
+
+		for _, spec := range specs {
+			tx := spec.Apply(db.Session(&gorm.Session{NewDB: true}).Model(db.Statement.Model))
+
+			if tx.Error != nil {
+				_ = db.AddError(tx.Error)
+				return db
+			}
+
+			where, ok := tx.Statement.Clauses["WHERE"].Expression.(clause.Where)
+
+			if !ok || len(where.Exprs) == 0 {
+				// a specification without conditions matches all the records
+				return db
+			}
+
+			exprs = append(exprs, clause.And(where.Exprs...))
+		}
+
+		switch len(exprs) {
+		case 0:
+			return db
+		case 1:
+			return db.Where(exprs[0])
+		}
+
+		return db.Where(clause.Or(exprs...))
+	})
+}
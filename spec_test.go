@@ -0,0 +1,52 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// olderThan matches the users older than its age
+type olderThan int
+
+func (s olderThan) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("age > ?", int(s))
+}
+
+// named matches the users with its name
+type named string
+
+func (s named) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("name = ?", string(s))
+}
+
+func TestSpecification(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	seed(t, repo, "alice", "bob", "carol", "carol")
+
+	var users []User
+
+	if err := repo.Find(ctx, &users, And(olderThan(21), named("carol"))); err != nil || len(users) != 2 || users[0].Age != 22 {
+		t.Fatalf("And: got %+v, %v, want both carols", users, err)
+	}
+
+	if err := repo.Find(ctx, &users, And(olderThan(22), named("carol"))); err != nil || len(users) != 1 || users[0].Age != 23 {
+		t.Fatalf("And: got %+v, %v, want the older carol", users, err)
+	}
+
+	if err := repo.Find(ctx, &users, Or(named("alice"), named("bob"))); err != nil || len(users) != 2 {
+		t.Fatalf("Or: got %+v, %v, want alice and bob", users, err)
+	}
+
+	if count, err := repo.Count(ctx, And(olderThan(20), Or(named("alice"), named("bob")))); err != nil || count != 1 {
+		t.Fatalf("And Or: got %d, %v, want bob", count, err)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, Or(named("bob"), olderThan(22))); err != nil || user.Name != "bob" {
+		t.Fatalf("First: got %+v, %v, want bob", user, err)
+	}
+}
@@ -0,0 +1,45 @@
+package regorm
+
+import (
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// identifier matches the schema names allowed by WithSchema, they are written into the SQL
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithTable returns a copy of the repository running its queries against the table name instead of
+// the table of the model, like the shard of a partitioned schema. The original repository is left untouched:
+//
+//	repo.WithTable("events_2024").Find(ctx, &events)
+func (r *Repository[T]) WithTable(name string) IRepository[T] {
+	clone := *r
+	clone.tableName = name
+
+	return &clone
+}
+
+// WithSchema returns a copy of the repository running its queries against the table in the database
+// schema, like the schema of a tenant in a multi-schema Postgres deployment. The original repository is left untouched:
+//
+//	repo.WithSchema("tenant1").Find(ctx, &users) // SELECT * FROM "tenant1"."users"
+//
+// The operations of the repository return ErrInvalidSchema if schema isn't made of letters, digits and underscores.
+func (r *Repository[T]) WithSchema(schema string) IRepository[T] {
+	clone := *r
+	clone.schemaName = schema
+
+	return &clone
+}
+
+// scopeTable applies the table of WithTable and the schema of WithSchema to db
+func (r *Repository[T]) scopeTable(db *gorm.DB) *gorm.DB {
+	if r.schemaName != "" && !identifier.MatchString(r.schemaName) {
+		_ = db.AddError(fmt.Errorf("%w: %q", ErrInvalidSchema, r.schemaName))
+		return db
+	}
+
+	return db.Table(r.table())
+}
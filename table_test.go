@@ -0,0 +1,139 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithTable(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	archive := repo.WithTable("users_archive")
+	ctx := context.Background()
+
+	if err := archive.AutoMigrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	seed(t, repo, "alice")
+	archived := seed(t, archive, "bob", "carol")
+
+	var users []User
+
+	if err := archive.Find(ctx, &users); err != nil || len(users) != 2 || users[0].Name != "bob" {
+		t.Fatalf("got %+v, %v, want the archived users", users, err)
+	}
+
+	if err := repo.Find(ctx, &users); err != nil || len(users) != 1 || users[0].Name != "alice" {
+		t.Fatalf("got %+v, %v, want the original repository reading users", users, err)
+	}
+
+	archived[0].Age = 40
+
+	if err := archive.Update(ctx, archived[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := archive.Delete(ctx, archived[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := archive.Find(ctx, &users); err != nil || len(users) != 1 || users[0].Age != 40 {
+		t.Fatalf("got %+v, %v, want bob updated and carol deleted", users, err)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, 1); err != nil || user.Name != "alice" || user.Age != 20 {
+		t.Fatalf("got %+v, %v, want alice untouched", user, err)
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	db := openDB(t)
+	sqlDB, err := db.DB()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// SQLite schemas are databases attached to a connection
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.Exec("ATTACH DATABASE ':memory:' AS tenant1").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// the SQLite insert builder drops the schema of tables, so the records are inserted with SQL
+	err = db.Exec("CREATE TABLE tenant1.users (id integer PRIMARY KEY, name text, email text, age integer, deleted_at datetime)").Error
+
+	if err == nil {
+		err = db.Exec("INSERT INTO tenant1.users (name, age) VALUES ('bob', 30)").Error
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := InitRepository[User](db)
+	tenant := repo.WithSchema("tenant1")
+	ctx := context.Background()
+	seed(t, repo, "alice")
+
+	var users []User
+
+	if err := tenant.Find(ctx, &users); err != nil || len(users) != 1 || users[0].Name != "bob" {
+		t.Fatalf("got %+v, %v, want the users of the schema", users, err)
+	}
+
+	if _, err := tenant.UpdateWhere(ctx, map[string]interface{}{"name": "bob"}, map[string]interface{}{"age": 31}); err != nil {
+		t.Fatal(err)
+	}
+
+	var user User
+
+	if err := tenant.First(ctx, &user); err != nil || user.Age != 31 {
+		t.Fatalf("got %+v, %v, want bob updated in the schema", user, err)
+	}
+
+	if err := repo.First(ctx, &user); err != nil || user.Name != "alice" || user.Age != 20 {
+		t.Fatalf("got %+v, %v, want the default schema untouched", user, err)
+	}
+
+	if err := repo.WithSchema("tenant1; DROP TABLE users").Find(ctx, &users); !errors.Is(err, ErrInvalidSchema) {
+		t.Fatalf("got %v, want ErrInvalidSchema", err)
+	}
+}
+
+func TestWithSchemaSQL(t *testing.T) {
+	db := openDialect(t, "postgres")
+	repo := InitRepository[User](db).WithSchema("tenant1")
+	rec := record(t, db)
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &User{Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Find(ctx, &[]User{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Delete(ctx, &User{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	sqls := rec.all()
+
+	if len(sqls) != 3 {
+		t.Fatalf("got %v, want 3 statements", sqls)
+	}
+
+	for _, sql := range sqls {
+		if !strings.Contains(sql, "`tenant1`.`users`") {
+			t.Errorf("got %q, want the table of the schema", sql)
+		}
+	}
+}
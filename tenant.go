@@ -0,0 +1,61 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantScopeKey marks the statements the tenant condition is already applied to
+const tenantScopeKey = "regorm:tenant_scope"
+
+// WithTenant returns a copy of the repository scoped to the tenant tenantID, every query, update and delete
+// it runs is filtered by tenantColumn = tenantID, even the WithTrashed ones, and the models it inserts or saves
+// get tenantColumn set to tenantID. The original repository is left untouched:
+//
+//	tenantRepo := repo.WithTenant("tenant_id", tenant.ID)
+//	tenantRepo.Find(ctx, &users) // only the users of tenant
+//
+// Raw, Exec and the handle returned by GetDB run SQL as given and aren't scoped.
+func (r *Repository[T]) WithTenant(tenantColumn string, tenantID interface{}) IRepository[T] {
+	clone := *r
+	clone.tenantColumn = tenantColumn
+	clone.tenantID = tenantID
+
+	return &clone
+}
+
+// scopeTenant is the scope filtering the statements by the tenant of repository
+func (r *Repository[T]) scopeTenant(db *gorm.DB) *gorm.DB {
+	if _, applied := db.InstanceGet(tenantScopeKey); applied {
+		return db
+	}
+
+	cond, err := r.tenantCondition()
+
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+
+	return db.InstanceSet(tenantScopeKey, true).Where(cond)
+}
+
+// tenantCondition builds the tenant column = tenant id condition, the column is validated against the model schema
+func (r *Repository[T]) tenantCondition() (clause.Expression, error) {
+	return r.equals(r.tenantColumn, r.tenantID)
+}
+
+// stampTenant sets the tenant column of models to the tenant of repository
+func (r *Repository[T]) stampTenant(ctx context.Context, models []*T) error {
+	if r.tenantColumn == "" {
+		return nil
+	}
+
+	if _, err := r.column(r.tenantColumn); err != nil {
+		return err
+	}
+
+	return r.stamp(ctx, r.tenantColumn, r.tenantID, models)
+}
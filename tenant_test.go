@@ -0,0 +1,57 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+)
+
+type Note struct {
+	ID       uint
+	TenantID uint
+	Text     string
+}
+
+func (Note) TableName() string { return "notes" }
+
+func TestWithTenant(t *testing.T) {
+	repo := InitRepository[Note](openDB(t, &Note{}))
+	ctx := context.Background()
+	acme, globex := repo.WithTenant("tenant_id", uint(1)), repo.WithTenant("tenant_id", uint(2))
+
+	mine := &Note{Text: "acme"}
+
+	if _, err := acme.Create(ctx, mine); err != nil || mine.TenantID != 1 {
+		t.Fatalf("got %+v, %v, want the tenant stamped", mine, err)
+	}
+
+	// the tenant of the repository wins over the one of the model
+	theirs := &Note{Text: "globex", TenantID: 1}
+
+	if _, err := globex.Create(ctx, theirs); err != nil || theirs.TenantID != 2 {
+		t.Fatalf("got %+v, %v, want the tenant stamped", theirs, err)
+	}
+
+	var notes []Note
+
+	if err := acme.Find(ctx, &notes); err != nil || len(notes) != 1 || notes[0].Text != "acme" {
+		t.Fatalf("got %+v, %v, want only the notes of acme", notes, err)
+	}
+
+	var note Note
+
+	if err := acme.First(ctx, &note, theirs.ID); err != nil || note.ID != 0 {
+		t.Fatalf("got %+v, %v, want the note of globex not found", note, err)
+	}
+
+	if n, err := acme.DeleteWhere(ctx, "text <> ''"); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want only the note of acme deleted", n, err)
+	}
+
+	if n, err := globex.UpdateColumns(ctx, "1 = 1", map[string]interface{}{"text": "changed"}); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want only the note of globex updated", n, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want the note of globex left", n, err)
+	}
+}
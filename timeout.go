@@ -0,0 +1,27 @@
+package regorm
+
+import (
+	"context"
+	"time"
+)
+
+// SetDefaultTimeout sets the timeout of the operations of repository called with a context without deadline,
+// so no query runs unbounded. The deadline of the context is kept when it has one, zero disables the timeout.
+// Transactions started with Begin, cursors opened with OpenCursor and streams started with Stream aren't bound
+// to the timeout, they live until they are ended by Commit or Rollback, closed, or until ctx is done.
+func (r *Repository[T]) SetDefaultTimeout(d time.Duration) {
+	r.timeout = d
+}
+
+// withTimeout returns ctx bounded by the default timeout of repository when it has no deadline
+func (r *Repository[T]) withTimeout(ctx context.Context, op string) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 || op == "Begin" || op == "OpenCursor" || op == "Stream" {
+		return ctx, func() {}
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, r.timeout)
+}
@@ -0,0 +1,53 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowQuery counts up to a billion, it runs for seconds on SQLite
+const slowQuery = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000000) SELECT COUNT(*) FROM c"
+
+func TestSetDefaultTimeout(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	repo.SetDefaultTimeout(50 * time.Millisecond)
+
+	var count int64
+	start := time.Now()
+
+	if err := repo.Raw(context.Background(), &count, slowQuery); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("got aborted after %v, want at the timeout", elapsed)
+	}
+
+	repo.SetDefaultTimeout(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := repo.Raw(ctx, &count, slowQuery); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the earlier deadline of the context to win", err)
+	}
+}
+
+func TestSetDefaultTimeoutStream(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	seed(t, repo, "alice", "bob", "carol")
+	repo.SetDefaultTimeout(20 * time.Millisecond)
+
+	items, errs := repo.Stream(context.Background())
+	var names []string
+
+	for item := range items {
+		names = append(names, item.Name)
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	if err := <-errs; err != nil || len(names) != 3 {
+		t.Fatalf("got %v, %v, want the stream outliving the timeout", names, err)
+	}
+}
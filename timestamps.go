@@ -0,0 +1,64 @@
+package regorm
+
+import (
+	"context"
+	"reflect"
+)
+
+// SetTimestampColumns sets the columns repository stamps with the current time, for models with timestamps
+// not named CreatedAt and UpdatedAt like created and modified. Create, CreateOmit, BatchCreate, FirstOrCreate
+// and Upsert set createdColumn when it's zero and updatedColumn, Update and UpdateOmit set updatedColumn.
+// An empty column isn't stamped, columns GORM already tracks with autoCreateTime or autoUpdateTime are left to GORM.
+func (r *Repository[T]) SetTimestampColumns(createdColumn, updatedColumn string) {
+	r.createdColumn = createdColumn
+	r.updatedColumn = updatedColumn
+}
+
+// stampTimestamps sets the created column when created is true and zero, and the updated column of models
+func (r *Repository[T]) stampTimestamps(ctx context.Context, created bool, models []*T) error {
+	if r.createdColumn == "" && r.updatedColumn == "" {
+		return nil
+	}
+
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return err
+	}
+
+	now := r.Database.NowFunc()
+
+	for _, model := range models {
+		modelValue := reflect.ValueOf(model).Elem()
+
+		if created && r.createdColumn != "" {
+			field, err := parseField(s, r.createdColumn)
+
+			if err != nil {
+				return err
+			}
+
+			if _, zero := field.ValueOf(ctx, modelValue); zero && field.AutoCreateTime == 0 {
+				if err := field.Set(ctx, modelValue, now); err != nil {
+					return err
+				}
+			}
+		}
+
+		if r.updatedColumn != "" {
+			field, err := parseField(s, r.updatedColumn)
+
+			if err != nil {
+				return err
+			}
+
+			if field.AutoUpdateTime == 0 {
+				if err := field.Set(ctx, modelValue, now); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type Stamped struct {
+	ID       uint
+	Name     string
+	Created  time.Time
+	Modified time.Time
+}
+
+func (Stamped) TableName() string { return "stamped" }
+
+func TestTimestampColumns(t *testing.T) {
+	repo := InitRepository[Stamped](openDB(t, &Stamped{}))
+	repo.SetTimestampColumns("created", "modified")
+	ctx := context.Background()
+	before := time.Now()
+	record := &Stamped{Name: "a"}
+
+	if _, err := repo.Create(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Created.Before(before) || !record.Modified.Equal(record.Created) {
+		t.Fatalf("got %+v, want both stamped on create", record)
+	}
+
+	created := record.Created
+	time.Sleep(10 * time.Millisecond)
+	record.Name = "b"
+
+	if err := repo.Update(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored Stamped
+
+	if err := repo.First(ctx, &stored, record.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Created.Equal(created) || !stored.Modified.After(created) {
+		t.Fatalf("got %+v, want only modified stamped on update", stored)
+	}
+}
@@ -0,0 +1,132 @@
+package regorm
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RunInTransaction runs fn inside a transaction, txRepo is bound to the transaction.
+// The transaction is committed if fn returns nil and rolled back if fn returns an error
+// or panics, in which case the panic is propagated after the rollback.
+func (r *Repository[T]) RunInTransaction(ctx context.Context, fn func(txRepo IRepository[T]) error) error {
+	return r.run(ctx, "RunInTransaction", func(ctx context.Context) (int64, error) {
+		return 0, r.db(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(r.withDB(tx))
+		})
+	})
+}
+
+// WithTx returns a copy of the repository bound to tx, a transaction owned by the caller.
+// The original repository is left untouched, this allows sharing one transaction between repositories:
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		if _, err := userRepo.WithTx(tx).Create(ctx, &user); err != nil {
+//			return err
+//		}
+//		_, err := orderRepo.WithTx(tx).Create(ctx, &order)
+//		return err
+//	})
+func (r *Repository[T]) WithTx(tx *gorm.DB) IRepository[T] {
+	return r.withDB(tx)
+}
+
+// Begin begins a transaction and returns a copy of the repository bound to it,
+// the transaction should be ended by calling Commit or Rollback on the returned repository
+func (r *Repository[T]) Begin(ctx context.Context) (IRepository[T], error) {
+	var tx *gorm.DB
+	err := r.run(ctx, "Begin", func(ctx context.Context) (int64, error) {
+		tx = r.db(ctx).Begin()
+
+		return 0, tx.Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.withDB(tx), nil
+}
+
+// Commit commits the transaction repository is bound to,
+// returns ErrNotInTransaction if repository isn't bound to a transaction
+func (r *Repository[T]) Commit() error {
+	if !r.inTransaction() {
+		return ErrNotInTransaction
+	}
+
+	// run invalidates the cache once committed, the writes of the transaction become visible
+	return r.run(context.Background(), "Commit", func(ctx context.Context) (int64, error) {
+		return 0, r.Database.Commit().Error
+	})
+}
+
+// Rollback rollbacks the transaction repository is bound to,
+// returns ErrNotInTransaction if repository isn't bound to a transaction
+func (r *Repository[T]) Rollback() error {
+	if !r.inTransaction() {
+		return ErrNotInTransaction
+	}
+
+	return r.run(context.Background(), "Rollback", func(ctx context.Context) (int64, error) {
+		return 0, r.Database.Rollback().Error
+	})
+}
+
+// SavePoint sets a savepoint with the given name inside the transaction repository is bound to,
+// returns ErrNotInTransaction if repository isn't bound to a transaction
+func (r *Repository[T]) SavePoint(name string) error {
+	if !r.inTransaction() {
+		return ErrNotInTransaction
+	}
+
+	return r.run(context.Background(), "SavePoint", func(ctx context.Context) (int64, error) {
+		return 0, r.Database.SavePoint(name).Error
+	})
+}
+
+// RollbackTo rollbacks the writes made after the savepoint with the given name,
+// returns ErrNotInTransaction if repository isn't bound to a transaction
+func (r *Repository[T]) RollbackTo(name string) error {
+	if !r.inTransaction() {
+		return ErrNotInTransaction
+	}
+
+	return r.run(context.Background(), "RollbackTo", func(ctx context.Context) (int64, error) {
+		return 0, r.Database.RollbackTo(name).Error
+	})
+}
+
+// FirstForUpdate finds the first record ordered by primary key matching given conditions and
+// locks it with SELECT ... FOR UPDATE until the transaction ends. The lock is only meaningful on
+// a repository bound to a transaction, outside of one it's released as soon as the query returns.
+func (r *Repository[T]) FirstForUpdate(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "FirstForUpdate", func(ctx context.Context) (int64, error) {
+		return 0, r.First(ctx, model, append([]interface{}{lock(clause.LockingStrengthUpdate)}, conds...)...)
+	})
+}
+
+// FirstForShare finds the first record ordered by primary key matching given conditions and
+// locks it with SELECT ... FOR SHARE until the transaction ends. The lock is only meaningful on
+// a repository bound to a transaction, outside of one it's released as soon as the query returns.
+func (r *Repository[T]) FirstForShare(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "FirstForShare", func(ctx context.Context) (int64, error) {
+		return 0, r.First(ctx, model, append([]interface{}{lock(clause.LockingStrengthShare)}, conds...)...)
+	})
+}
+
+// lock returns a query option locking the selected records with strength
+func lock(strength string) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Clauses(clause.Locking{Strength: strength})
+	}
+}
+
+// inTransaction reports whether repository is bound to a transaction
+func (r *Repository[T]) inTransaction() bool {
+	committer, ok := r.Database.Statement.ConnPool.(gorm.TxCommitter)
+
+	return ok && committer != nil && !reflect.ValueOf(committer).IsNil()
+}
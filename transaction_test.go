@@ -0,0 +1,276 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunInTransaction(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	err := repo.RunInTransaction(ctx, func(txRepo IRepository[User]) error {
+		if _, err := txRepo.Create(ctx, &User{Name: "alice"}); err != nil {
+			return err
+		}
+
+		return failure
+	})
+
+	if !errors.Is(err, failure) {
+		t.Fatalf("got %v, want the error of fn", err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want the failed transaction rolled back", n, err)
+	}
+
+	err = repo.RunInTransaction(ctx, func(txRepo IRepository[User]) error {
+		if _, err := txRepo.Create(ctx, &User{Name: "alice"}); err != nil {
+			return err
+		}
+
+		_, err := txRepo.Create(ctx, &User{Name: "bob"})
+
+		return err
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want both writes committed", n, err)
+	}
+}
+
+func TestRunInTransactionPanic(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Fatalf("got %v, want the panic propagated", recovered)
+			}
+		}()
+
+		_ = repo.RunInTransaction(ctx, func(txRepo IRepository[User]) error {
+			if _, err := txRepo.Create(ctx, &User{Name: "alice"}); err != nil {
+				return err
+			}
+
+			panic("boom")
+		})
+	}()
+
+	if n, err := repo.Count(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want the transaction rolled back", n, err)
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	db := openDB(t)
+	repo := InitRepository[User](db)
+	ctx := context.Background()
+
+	tx := db.Begin()
+	txRepo := repo.WithTx(tx)
+
+	if _, err := txRepo.Create(ctx, &User{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := txRepo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want the write visible inside the transaction", n, err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want the write invisible before commit", n, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want the write visible after commit", n, err)
+	}
+
+	if repo.GetDB() != db {
+		t.Fatal("want the original repository untouched")
+	}
+}
+
+func TestBeginCommitRollback(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+
+	if err := repo.Commit(); !errors.Is(err, ErrNotInTransaction) {
+		t.Fatalf("Commit: got %v, want ErrNotInTransaction", err)
+	}
+
+	if err := repo.Rollback(); !errors.Is(err, ErrNotInTransaction) {
+		t.Fatalf("Rollback: got %v, want ErrNotInTransaction", err)
+	}
+
+	txRepo, err := repo.Begin(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := txRepo.Create(ctx, &User{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := txRepo.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want the write rolled back", n, err)
+	}
+
+	txRepo, err = repo.Begin(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := txRepo.Create(ctx, &User{Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := txRepo.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want the write committed", n, err)
+	}
+}
+
+func TestSavePoint(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+
+	if err := repo.SavePoint("sp"); !errors.Is(err, ErrNotInTransaction) {
+		t.Fatalf("got %v, want ErrNotInTransaction", err)
+	}
+
+	err := repo.RunInTransaction(ctx, func(txRepo IRepository[User]) error {
+		if _, err := txRepo.Create(ctx, &User{Name: "before"}); err != nil {
+			return err
+		}
+
+		if err := txRepo.SavePoint("sp"); err != nil {
+			return err
+		}
+
+		if _, err := txRepo.Create(ctx, &User{Name: "after"}); err != nil {
+			return err
+		}
+
+		return txRepo.RollbackTo("sp")
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var users []User
+
+	if err := repo.Find(ctx, &users); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 1 || users[0].Name != "before" {
+		t.Fatalf("got %+v, want only the write before the savepoint", users)
+	}
+}
+
+func TestFirstForUpdateSQL(t *testing.T) {
+	db := openDialect(t, "postgres")
+	repo := InitRepository[User](db)
+	rec := record(t, db)
+	ctx := context.Background()
+
+	var user User
+
+	if err := repo.FirstForUpdate(ctx, &user, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); !strings.HasSuffix(sql, "FOR UPDATE") {
+		t.Fatalf("got %q, want FOR UPDATE", sql)
+	}
+
+	if err := repo.FirstForShare(ctx, &user, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); !strings.HasSuffix(sql, "FOR SHARE") {
+		t.Fatalf("got %q, want FOR SHARE", sql)
+	}
+}
+
+func TestFirstForUpdateSerializes(t *testing.T) {
+	// SQLite has no row locks, BEGIN IMMEDIATE locks the database for the whole transaction instead
+	repo := InitRepository[Product](openDSN(t, "&_txlock=immediate", &Product{}))
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &Product{Code: "counter"}); err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	increment := func(hold bool) {
+		defer wg.Done()
+
+		err := repo.RunInTransaction(ctx, func(txRepo IRepository[Product]) error {
+			var product Product
+
+			if err := txRepo.FirstForUpdate(ctx, &product, "code = ?", "counter"); err != nil {
+				return err
+			}
+
+			if hold {
+				close(locked)
+				<-release
+			}
+
+			product.Price++
+
+			return txRepo.Update(ctx, &product)
+		})
+
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	go increment(true)
+	<-locked
+	go increment(false)
+
+	// the second transaction waits for the lock of the first one
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	var product Product
+
+	if err := repo.First(ctx, &product, "code = ?", "counter"); err != nil || product.Price != 2 {
+		t.Fatalf("got %+v, %v, want both increments applied", product, err)
+	}
+}
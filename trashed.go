@@ -0,0 +1,101 @@
+package regorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// FirstWithTrashed finds the first record ordered by primary key, matching given conditions
+// including the soft deleted records
+func (r *Repository[T]) FirstWithTrashed(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "FirstWithTrashed", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Unscoped().First(model)
+
+		if res.Error != nil && res.Error != gorm.ErrRecordNotFound {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// FindWithTrashed finds all the records matching given conditions including the soft deleted records
+func (r *Repository[T]) FindWithTrashed(ctx context.Context, models *[]T, conds ...interface{}) error {
+	return r.run(ctx, "FindWithTrashed", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Unscoped().Find(models)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, models)
+	})
+}
+
+// CountWithTrashed counts the records matching given conditions including the soft deleted records
+func (r *Repository[T]) CountWithTrashed(ctx context.Context, conds ...interface{}) (int64, error) {
+	var count int64
+	err := r.run(ctx, "CountWithTrashed", func(ctx context.Context) (int64, error) {
+		res := r.query(ctx, conds).Unscoped().Count(&count)
+
+		return res.RowsAffected, res.Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FirstOnlyTrashed finds the first soft deleted record ordered by primary key, matching given conditions.
+// Returns ErrNotSoftDeletable if the model has no gorm.DeletedAt field nor soft delete column.
+func (r *Repository[T]) FirstOnlyTrashed(ctx context.Context, model *T, conds ...interface{}) error {
+	return r.run(ctx, "FirstOnlyTrashed", func(ctx context.Context) (int64, error) {
+		db, err := r.onlyTrashed(ctx, conds)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := db.First(model)
+
+		if res.Error != nil && res.Error != gorm.ErrRecordNotFound {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, model)
+	})
+}
+
+// FindOnlyTrashed finds all the soft deleted records matching given conditions.
+// Returns ErrNotSoftDeletable if the model has no gorm.DeletedAt field nor soft delete column.
+func (r *Repository[T]) FindOnlyTrashed(ctx context.Context, models *[]T, conds ...interface{}) error {
+	return r.run(ctx, "FindOnlyTrashed", func(ctx context.Context) (int64, error) {
+		db, err := r.onlyTrashed(ctx, conds)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := db.Find(models)
+
+		if res.Error != nil {
+			return 0, res.Error
+		}
+
+		return res.RowsAffected, r.decrypt(ctx, models)
+	})
+}
+
+// onlyTrashed returns a query matching only the soft deleted records
+func (r *Repository[T]) onlyTrashed(ctx context.Context, conds []interface{}) (*gorm.DB, error) {
+	field, err := r.softDeleteField()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.query(ctx, conds).Unscoped().Where(trashed(field)), nil
+}
@@ -0,0 +1,66 @@
+package regorm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithTrashed(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice", "bob")
+
+	if _, err := repo.Delete(ctx, users[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	var found []User
+
+	if err := repo.Find(ctx, &found); err != nil || len(found) != 1 || found[0].Name != "bob" {
+		t.Fatalf("Find: got %+v, %v, want only bob", found, err)
+	}
+
+	if err := repo.FindWithTrashed(ctx, &found); err != nil || len(found) != 2 {
+		t.Fatalf("FindWithTrashed: got %+v, %v, want both", found, err)
+	}
+
+	if err := repo.FindOnlyTrashed(ctx, &found); err != nil || len(found) != 1 || found[0].Name != "alice" {
+		t.Fatalf("FindOnlyTrashed: got %+v, %v, want only alice", found, err)
+	}
+
+	var user User
+
+	if err := repo.FirstWithTrashed(ctx, &user, "name = ?", "alice"); err != nil || user.ID != users[0].ID {
+		t.Fatalf("FirstWithTrashed: got %+v, %v, want alice", user, err)
+	}
+
+	user = User{}
+
+	if err := repo.FirstOnlyTrashed(ctx, &user, "name = ?", "bob"); err != nil || user.ID != 0 {
+		t.Fatalf("FirstOnlyTrashed: got %+v, %v, want bob not found", user, err)
+	}
+}
+
+func TestCountWithTrashed(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice", "bob", "carol", "dave", "erin")
+
+	for _, user := range users[3:] {
+		if _, err := repo.Delete(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 3 {
+		t.Fatalf("Count: got %d, %v, want 3", count, err)
+	}
+
+	if count, err := repo.CountWithTrashed(ctx); err != nil || count != 5 {
+		t.Fatalf("CountWithTrashed: got %d, %v, want 5", count, err)
+	}
+
+	if count, err := repo.CountWithTrashed(ctx, "age > ?", 22); err != nil || count != 2 {
+		t.Fatalf("CountWithTrashed: got %d, %v, want dave and erin", count, err)
+	}
+}
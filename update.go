@@ -0,0 +1,199 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// UpdateColumns updates only the given columns of the records matching conds and returns rows affected,
+// unlike Update other columns aren't written so concurrent changes to them are kept
+//
+//	repo.UpdateColumns(ctx, map[string]interface{}{"id": 1}, map[string]interface{}{"name": "new name"})
+func (r *Repository[T]) UpdateColumns(ctx context.Context, conds interface{}, values map[string]interface{}) (int64, error) {
+	return r.runRows(ctx, "UpdateColumns", func(ctx context.Context) (int64, error) {
+		values, err := r.encryptedColumns(values)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res := r.db(ctx).Model(new(T)).Where(conds).Updates(values)
+
+		return res.RowsAffected, res.Error
+	})
+}
+
+// UpdateWhere updates the given columns of all the records matching conds in a single statement
+// and returns rows affected. Returns ErrMissingCondition if conds is empty to avoid updating the whole table.
+//
+//	repo.UpdateWhere(ctx, map[string]interface{}{"status": "pending"}, map[string]interface{}{"status": "expired"})
+func (r *Repository[T]) UpdateWhere(ctx context.Context, conds interface{}, values map[string]interface{}) (int64, error) {
+	return r.runRows(ctx, "UpdateWhere", func(ctx context.Context) (int64, error) {
+		if emptyCondition(conds) {
+			return 0, ErrMissingCondition
+		}
+
+		return r.UpdateColumns(ctx, conds, values)
+	})
+}
+
+// Increment atomically adds delta to column of the records matching conds,
+// runs UPDATE ... SET column = column + delta and returns rows affected.
+// column is validated against the model schema.
+func (r *Repository[T]) Increment(ctx context.Context, conds interface{}, column string, delta int64) (int64, error) {
+	return r.runRows(ctx, "Increment", func(ctx context.Context) (int64, error) {
+		return r.increment(ctx, conds, column, delta)
+	})
+}
+
+// Decrement atomically subtracts delta from column of the records matching conds,
+// runs UPDATE ... SET column = column - delta and returns rows affected.
+// column is validated against the model schema.
+func (r *Repository[T]) Decrement(ctx context.Context, conds interface{}, column string, delta int64) (int64, error) {
+	return r.runRows(ctx, "Decrement", func(ctx context.Context) (int64, error) {
+		return r.increment(ctx, conds, column, -delta)
+	})
+}
+
+// increment runs UPDATE ... SET column = column + delta over the records matching conds
+func (r *Repository[T]) increment(ctx context.Context, conds interface{}, column string, delta int64) (int64, error) {
+	name, err := r.column(column)
+
+	if err != nil {
+		return 0, err
+	}
+
+	res := r.db(ctx).Model(new(T)).Where(conds).
+		UpdateColumn(name, gorm.Expr("? + ?", clause.Column{Name: name}, delta))
+
+	return res.RowsAffected, res.Error
+}
+
+// save saves value and returns rows affected, values with an integer Version field are updated with optimistic locking
+func (r *Repository[T]) save(ctx context.Context, db *gorm.DB, model *T) (int64, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return 0, err
+	}
+
+	modelValue := reflect.ValueOf(model).Elem()
+	version := versionField(s)
+
+	if !primaryKeyZero(ctx, s, modelValue) {
+		// the record may have been read without its masked columns
+		db = r.omitMasked(db)
+	}
+
+	if primaryKeyZero(ctx, s, modelValue) || version == nil && r.tenantColumn == "" {
+		res := db.Save(model)
+
+		return res.RowsAffected, res.Error
+	}
+
+	if version == nil {
+		return r.saveTenant(ctx, db, s, model)
+	}
+
+	value := version.ReflectValueOf(ctx, modelValue)
+	current := value.Interface()
+	bumpVersion(value, 1)
+
+	res := db.Model(model).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: version.DBName}, Value: current}).
+		Select("*").
+		Updates(model)
+
+	if res.Error != nil {
+		bumpVersion(value, -1)
+		return 0, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		bumpVersion(value, -1)
+		return 0, ErrOptimisticLock
+	}
+
+	return res.RowsAffected, nil
+}
+
+// updateExisting updates value and returns rows affected, returns ErrNotFound if it isn't stored
+func (r *Repository[T]) updateExisting(ctx context.Context, db *gorm.DB, model *T) (int64, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return 0, err
+	}
+
+	modelValue := reflect.ValueOf(model).Elem()
+
+	if primaryKeyZero(ctx, s, modelValue) {
+		return 0, notFound(gorm.ErrRecordNotFound)
+	}
+
+	db = r.omitMasked(db)
+
+	var rows int64
+
+	if versionField(s) == nil {
+		res := db.Model(model).Select("*").Updates(model)
+		rows, err = res.RowsAffected, res.Error
+	} else {
+		rows, err = r.save(ctx, db, model)
+	}
+
+	if rows > 0 || err != nil && !errors.Is(err, ErrOptimisticLock) {
+		return rows, err
+	}
+
+	// rows affected is zero for unchanged records on some databases, and for outdated versions
+	var count int64
+	res := r.db(ctx).Model(new(T)).Where(primaryKeyCondition(ctx, s, modelValue)).Count(&count)
+
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	if count == 0 {
+		return 0, notFound(gorm.ErrRecordNotFound)
+	}
+
+	return 0, err
+}
+
+// saveTenant saves value of a tenant scoped repository. Unlike Save it never falls back to an upsert
+// which could overwrite the record of another tenant, value is inserted only if it isn't stored yet.
+func (r *Repository[T]) saveTenant(ctx context.Context, db *gorm.DB, s *schema.Schema, model *T) (int64, error) {
+	res := db.Model(model).Select("*").Updates(model)
+
+	if res.Error != nil || res.RowsAffected > 0 {
+		return res.RowsAffected, res.Error
+	}
+
+	// rows affected is zero for unchanged records on some databases
+	var count int64
+	res = r.db(ctx).Model(new(T)).Where(primaryKeyCondition(ctx, s, reflect.ValueOf(model).Elem())).Count(&count)
+
+	if res.Error != nil || count > 0 {
+		return 0, res.Error
+	}
+
+	res = db.Create(model)
+
+	return res.RowsAffected, res.Error
+}
+
+// bumpVersion adds delta to an integer version field value
+func bumpVersion(value reflect.Value, delta int64) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(value.Int() + delta)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(int64(value.Uint()) + delta))
+	}
+}
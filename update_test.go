@@ -0,0 +1,173 @@
+package regorm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestIncrement(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &Product{Code: "p1", Price: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				if _, err := repo.Increment(ctx, map[string]interface{}{"code": "p1"}, "price", 3); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	n, err := repo.Decrement(ctx, map[string]interface{}{"code": "p1"}, "price", 1)
+
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	var product Product
+
+	if err := repo.First(ctx, &product); err != nil || product.Price != 60 {
+		t.Fatalf("got %+v, %v, want price 60", product, err)
+	}
+
+	if _, err := repo.Increment(ctx, map[string]interface{}{"code": "p1"}, "price = 0, name", 1); !errors.Is(err, ErrInvalidColumn) {
+		t.Fatalf("got %v, want ErrInvalidColumn", err)
+	}
+}
+
+func TestUpdateColumns(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice")
+
+	if err := repo.GetDB().Model(&User{}).Where("id = ?", users[0].ID).Update("email", "changed@example.com").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := repo.UpdateColumns(ctx, map[string]interface{}{"id": users[0].ID}, map[string]interface{}{"name": "alicia"})
+
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 row affected", n, err)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, users[0].ID); err != nil || user.Name != "alicia" || user.Email != "changed@example.com" {
+		t.Fatalf("got %+v, %v, want only name updated", user, err)
+	}
+}
+
+func TestUpdateWhere(t *testing.T) {
+	repo := InitRepository[Purchase](openDB(t, &Purchase{}))
+	ctx := context.Background()
+
+	purchases := []*Purchase{{Status: "pending"}, {Status: "paid"}, {Status: "pending"}, {Status: "paid"}, {Status: "pending"}}
+
+	if _, err := repo.BatchCreate(ctx, purchases); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := repo.UpdateWhere(ctx, map[string]interface{}{"status": "pending"}, map[string]interface{}{"status": "expired"})
+
+	if err != nil || n != 3 {
+		t.Fatalf("got %d, %v, want 3 rows affected", n, err)
+	}
+
+	if n, err := repo.Count(ctx, "status = ?", "expired"); err != nil || n != 3 {
+		t.Fatalf("got %d, %v, want 3 expired", n, err)
+	}
+
+	if n, err := repo.Count(ctx, "status = ?", "paid"); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2 paid untouched", n, err)
+	}
+
+	if _, err := repo.UpdateWhere(ctx, map[string]interface{}{}, map[string]interface{}{"status": "expired"}); !errors.Is(err, ErrMissingCondition) {
+		t.Fatalf("got %v, want ErrMissingCondition", err)
+	}
+}
+
+type Document struct {
+	ID      uint
+	Title   string
+	Version int
+}
+
+func (Document) TableName() string { return "documents" }
+
+func TestOptimisticLock(t *testing.T) {
+	repo := InitRepository[Document](openDB(t, &Document{}))
+	ctx := context.Background()
+	doc := &Document{Title: "draft", Version: 1}
+
+	if _, err := repo.Create(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := *doc
+	doc.Title = "first edit"
+
+	if err := repo.Update(ctx, doc); err != nil || doc.Version != 2 {
+		t.Fatalf("got version %d, %v, want the fresh update bumping the version", doc.Version, err)
+	}
+
+	stale.Title = "stale edit"
+
+	if err := repo.Update(ctx, &stale); !errors.Is(err, ErrOptimisticLock) || stale.Version != 1 {
+		t.Fatalf("got version %d, %v, want ErrOptimisticLock with the version untouched", stale.Version, err)
+	}
+
+	var stored Document
+
+	if err := repo.First(ctx, &stored, doc.ID); err != nil || stored.Title != "first edit" || stored.Version != 2 {
+		t.Fatalf("got %+v, %v, want the first edit kept", stored, err)
+	}
+}
+
+func TestUpdateExisting(t *testing.T) {
+	repo := InitRepository[User](openDB(t))
+	ctx := context.Background()
+	users := seed(t, repo, "alice")
+
+	users[0].Age = 30
+
+	if rows, err := repo.UpdateExisting(ctx, users[0]); err != nil || rows != 1 {
+		t.Fatalf("got %d, %v, want alice updated", rows, err)
+	}
+
+	if rows, err := repo.UpdateExisting(ctx, users[0]); err != nil {
+		t.Fatalf("got %d, %v, want an unchanged record updated", rows, err)
+	}
+
+	if _, err := repo.UpdateExisting(ctx, &User{ID: 42, Name: "ghost"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+
+	if _, err := repo.UpdateExisting(ctx, &User{Name: "nobody"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound without a primary key", err)
+	}
+
+	if n, err := repo.Count(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want nothing inserted", n, err)
+	}
+
+	var user User
+
+	if err := repo.First(ctx, &user, users[0].ID); err != nil || user.Age != 30 {
+		t.Fatalf("got %+v, %v, want age 30", user, err)
+	}
+}
@@ -0,0 +1,438 @@
+package regorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// upsertCreatedColumn is the alias of the column returned by UpsertReturning on PostgreSQL
+const upsertCreatedColumn = "regorm_created"
+
+// Upsert inserts value, if value conflicts with an existing record on conflictColumns
+// the updateColumns of the existing record are updated from value instead.
+// Conflicting records are left untouched when updateColumns is empty. Repository hooks aren't run, see BeforeCreateHook.
+func (r *Repository[T]) Upsert(ctx context.Context, model *T, conflictColumns []string, updateColumns []string) error {
+	return r.run(ctx, "Upsert", func(ctx context.Context) (int64, error) {
+		onConflict, err := r.onConflict(conflictColumns, updateColumns)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err := r.prepareCreate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return r.encrypted(ctx, []*T{model}, r.retried(ctx, func() (int64, error) {
+			res := r.db(ctx).Clauses(onConflict).Create(model)
+
+			return res.RowsAffected, res.Error
+		}))()
+	})
+}
+
+// UpsertReturning upserts value like Upsert and reports whether it was inserted, created is false when value
+// conflicted with an existing record on conflictColumns, or on the primary key when conflictColumns is empty.
+// The outcome is returned by the upsert itself on PostgreSQL, through RETURNING (xmax = 0), and told by
+// the rows affected on MySQL, as long as the connection doesn't set clientFoundRows. Other databases look
+// the existing record up before the upsert in the same transaction, which isn't safe against concurrent
+// inserts: a record inserted by another connection in between is reported as created.
+func (r *Repository[T]) UpsertReturning(ctx context.Context, model *T, conflictColumns []string, updateColumns []string) (bool, error) {
+	var created bool
+	err := r.run(ctx, "UpsertReturning", func(ctx context.Context) (int64, error) {
+		onConflict, err := r.onConflict(conflictColumns, updateColumns)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err := r.prepareCreate(ctx, []*T{model}); err != nil {
+			return 0, err
+		}
+
+		return r.encrypted(ctx, []*T{model}, r.retried(ctx, func() (int64, error) {
+			var rows int64
+
+			switch r.Database.Dialector.Name() {
+			case "postgres":
+				rows, created, err = r.upsertPostgres(ctx, model, onConflict)
+			case "mysql":
+				// an insert affects 1 row, an update 2 and an update leaving the record unchanged none
+				res := r.db(ctx).Clauses(onConflict).Create(model)
+				rows, created, err = res.RowsAffected, res.RowsAffected == 1, res.Error
+			default:
+				rows, created, err = r.upsertLookingUp(ctx, model, onConflict)
+			}
+
+			return rows, err
+		}))()
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+// upsertPostgres upserts value returning whether it was inserted, the row version written by an update
+// on conflict is locked by the statement so its xmax isn't zero
+func (r *Repository[T]) upsertPostgres(ctx context.Context, model *T, onConflict clause.OnConflict) (int64, bool, error) {
+	db := r.db(ctx)
+	s, err := parseSchema(db, new(T))
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	columns := make([]clause.Column, 0, len(s.FieldsWithDefaultDBValue)+1)
+
+	for _, field := range s.FieldsWithDefaultDBValue {
+		if field.Readable {
+			columns = append(columns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	columns = append(columns, clause.Column{Name: "(xmax = 0)", Alias: upsertCreatedColumn, Raw: true})
+
+	// GORM drops the returned columns unknown to the model, the insert it builds is run as a query instead
+	stmt := db.Session(&gorm.Session{DryRun: true}).Clauses(onConflict, clause.Returning{Columns: columns}).Create(model)
+
+	if stmt.Error != nil || db.DryRun {
+		return 0, false, stmt.Error
+	}
+
+	var returned []map[string]interface{}
+
+	if err := db.Raw(stmt.Statement.SQL.String(), stmt.Statement.Vars...).Scan(&returned).Error; err != nil {
+		return 0, false, err
+	}
+
+	// nothing is returned when the conflicting record is left untouched
+	if len(returned) == 0 {
+		return 0, false, nil
+	}
+
+	modelValue := reflect.ValueOf(model).Elem()
+
+	for _, field := range s.FieldsWithDefaultDBValue {
+		if value, ok := returned[0][field.DBName]; ok {
+			if err := field.Set(ctx, modelValue, value); err != nil {
+				return 0, false, err
+			}
+		}
+	}
+
+	created, _ := returned[0][upsertCreatedColumn].(bool)
+
+	return 1, created, nil
+}
+
+// upsertLookingUp upserts value in a transaction after looking up the record it conflicts with
+func (r *Repository[T]) upsertLookingUp(ctx context.Context, model *T, onConflict clause.OnConflict) (int64, bool, error) {
+	cond, err := r.conflictCondition(ctx, model, onConflict.Columns)
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	var (
+		rows    int64
+		created bool
+	)
+	err = r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+
+		if err := tx.Model(new(T)).Unscoped().Where(cond).Count(&count).Error; err != nil {
+			return err
+		}
+
+		res := tx.Clauses(onConflict).Create(model)
+		rows, created = res.RowsAffected, count == 0
+
+		return res.Error
+	})
+
+	return rows, created, err
+}
+
+// BatchUpsert inserts values in a single multi-row statement and returns rows affected, values conflicting
+// with existing records on conflictColumns update the updateColumns of these records instead.
+// Conflicting records are left untouched when updateColumns is empty, like the values conflicting with them,
+// see createDoingNothing. The statement is only split
+// when the database sets gorm.Config.CreateBatchSize. Repository hooks aren't run, see BeforeCreateHook.
+func (r *Repository[T]) BatchUpsert(ctx context.Context, models []*T, conflictColumns []string, updateColumns []string) (int64, error) {
+	return r.runRows(ctx, "BatchUpsert", func(ctx context.Context) (int64, error) {
+		if len(models) == 0 {
+			return 0, nil
+		}
+
+		onConflict, err := r.onConflict(conflictColumns, updateColumns)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err := r.prepareCreate(ctx, models); err != nil {
+			return 0, err
+		}
+
+		return r.encrypted(ctx, models, r.retried(ctx, func() (int64, error) {
+			if onConflict.DoNothing {
+				return r.createDoingNothing(ctx, models, onConflict)
+			}
+
+			res := r.db(ctx).Clauses(onConflict).Create(models)
+
+			return res.RowsAffected, res.Error
+		}))()
+	})
+}
+
+// CreateIgnore inserts values in a single statement skipping the ones conflicting with existing records,
+// like a duplicate primary or unique key, and returns the number of records actually inserted.
+// The skipped values are left untouched, see createDoingNothing for the generated columns like the auto
+// increment primary key of the inserted ones. Repository hooks aren't run, see BeforeCreateHook.
+func (r *Repository[T]) CreateIgnore(ctx context.Context, models []*T) (int64, error) {
+	return r.runRows(ctx, "CreateIgnore", func(ctx context.Context) (int64, error) {
+		if len(models) == 0 {
+			return 0, nil
+		}
+
+		if err := r.prepareCreate(ctx, models); err != nil {
+			return 0, err
+		}
+
+		return r.encrypted(ctx, models, r.retried(ctx, func() (int64, error) {
+			return r.createDoingNothing(ctx, models, clause.OnConflict{DoNothing: true})
+		}))()
+	})
+}
+
+// createDoingNothing inserts values skipping the ones conflicting with existing records on onConflict and returns
+// the number of records inserted. GORM reads the generated columns back by position, which assigns the ones of
+// the inserted records to the skipped values, so they are returned with the whole records and matched to the
+// values by their conflict columns, or by their primary and unique keys when onConflict has none. The databases
+// without RETURNING like MySQL don't read the generated columns back, they are left unset.
+func (r *Repository[T]) createDoingNothing(ctx context.Context, models []*T, onConflict clause.OnConflict) (int64, error) {
+	db := r.db(ctx)
+	s, err := parseSchema(db, new(T))
+
+	if err != nil {
+		return 0, err
+	}
+
+	generated := s.FieldsWithDefaultDBValue
+
+	if !slices.Contains(db.Callback().Create().Clauses, "RETURNING") {
+		values := make([][]reflect.Value, len(models))
+
+		for i, model := range models {
+			for _, field := range generated {
+				values[i] = append(values[i], reflect.ValueOf(field.ReflectValueOf(ctx, reflect.ValueOf(model).Elem()).Interface()))
+			}
+		}
+
+		res := db.Clauses(onConflict).Create(models)
+
+		// restore the generated columns GORM assigned from the last insert id
+		for i, model := range models {
+			for j, field := range generated {
+				field.ReflectValueOf(ctx, reflect.ValueOf(model).Elem()).Set(values[i][j])
+			}
+		}
+
+		return res.RowsAffected, res.Error
+	}
+
+	// GORM reads back the returned columns into the values by position, the insert it builds is run as a query instead
+	stmt := db.Session(&gorm.Session{DryRun: true}).Clauses(onConflict, clause.Returning{}).Create(models)
+
+	if stmt.Error != nil || db.DryRun {
+		return 0, stmt.Error
+	}
+
+	var returned []T
+
+	if err := db.Raw(stmt.Statement.SQL.String(), stmt.Statement.Vars...).Scan(&returned).Error; err != nil {
+		return 0, err
+	}
+
+	keys := conflictKeys(s, onConflict.Columns)
+	inserted := map[string][]int{}
+
+	for i := range returned {
+		for _, key := range keyValues(ctx, keys, reflect.ValueOf(&returned[i]).Elem()) {
+			inserted[key] = append(inserted[key], i)
+		}
+	}
+
+	matched := make([]bool, len(returned))
+
+	for _, model := range models {
+		modelValue := reflect.ValueOf(model).Elem()
+
+		for _, key := range keyValues(ctx, keys, modelValue) {
+			i := slices.IndexFunc(inserted[key], func(i int) bool { return !matched[i] })
+
+			if i < 0 {
+				continue
+			}
+
+			row := reflect.ValueOf(&returned[inserted[key][i]]).Elem()
+			matched[inserted[key][i]] = true
+
+			for _, field := range generated {
+				field.ReflectValueOf(ctx, modelValue).Set(field.ReflectValueOf(ctx, row))
+			}
+
+			break
+		}
+	}
+
+	return int64(len(returned)), nil
+}
+
+// conflictKeys returns the fields of the keys the inserted records conflict on, the fields of columns, or else
+// the primary key and the unique keys of the schema s
+func conflictKeys(s *schema.Schema, columns []clause.Column) [][]*schema.Field {
+	if len(columns) > 0 {
+		key := make([]*schema.Field, 0, len(columns))
+
+		for _, column := range columns {
+			key = append(key, s.LookUpField(column.Name))
+		}
+
+		return [][]*schema.Field{key}
+	}
+
+	keys := [][]*schema.Field{s.PrimaryFields}
+
+	for _, field := range s.Fields {
+		if field.Unique {
+			keys = append(keys, []*schema.Field{field})
+		}
+	}
+
+	for _, index := range s.ParseIndexes() {
+		if index.Class != "UNIQUE" {
+			continue
+		}
+
+		key := make([]*schema.Field, 0, len(index.Fields))
+
+		for _, option := range index.Fields {
+			key = append(key, option.Field)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// keyValues returns the values of the keys of value as strings, the keys whose fields are all zero are skipped
+func keyValues(ctx context.Context, keys [][]*schema.Field, value reflect.Value) []string {
+	values := make([]string, 0, len(keys))
+
+	for i, key := range keys {
+		parts := make([]interface{}, 0, len(key))
+		allZero := true
+
+		for _, field := range key {
+			part, zero := field.ValueOf(ctx, value)
+			parts = append(parts, part)
+			allZero = allZero && zero
+		}
+
+		if len(key) > 0 && !allZero {
+			values = append(values, fmt.Sprintf("%d:%v", i, parts))
+		}
+	}
+
+	return values
+}
+
+// conflictCondition builds the condition matching the records value conflicts with on columns, or on the primary key when columns is empty
+func (r *Repository[T]) conflictCondition(ctx context.Context, model *T, columns []clause.Column) (clause.Expression, error) {
+	s, err := parseSchema(r.Database, new(T))
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelValue := reflect.ValueOf(model).Elem()
+
+	if len(columns) == 0 {
+		return primaryKeyCondition(ctx, s, modelValue), nil
+	}
+
+	conds := make([]clause.Expression, 0, len(columns))
+
+	for _, column := range columns {
+		field, err := parseField(s, column.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, _ := field.ValueOf(ctx, modelValue)
+		conds = append(conds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+	}
+
+	return clause.And(conds...), nil
+}
+
+// onConflict builds the ON CONFLICT clause for upserts, columns are validated against the model schema
+func (r *Repository[T]) onConflict(conflictColumns []string, updateColumns []string) (clause.OnConflict, error) {
+	onConflict := clause.OnConflict{}
+
+	for _, column := range conflictColumns {
+		name, err := r.column(column)
+
+		if err != nil {
+			return onConflict, err
+		}
+
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: name})
+	}
+
+	if len(updateColumns) == 0 {
+		onConflict.DoNothing = true
+
+		return onConflict, nil
+	}
+
+	names := make([]string, 0, len(updateColumns))
+
+	for _, column := range updateColumns {
+		name, err := r.column(column)
+
+		if err != nil {
+			return onConflict, err
+		}
+
+		names = append(names, name)
+	}
+
+	onConflict.DoUpdates = clause.AssignmentColumns(names)
+
+	if r.tenantColumn != "" {
+		cond, err := r.tenantCondition()
+
+		if err != nil {
+			return onConflict, err
+		}
+
+		// never update the conflicting record of another tenant
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{cond}}
+	}
+
+	return onConflict, nil
+}
@@ -0,0 +1,233 @@
+package regorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type Product struct {
+	ID    uint
+	Code  string `gorm:"uniqueIndex"`
+	Name  string
+	Price int
+}
+
+func (Product) TableName() string { return "products" }
+
+func TestUpsert(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+
+	if err := repo.Upsert(ctx, &Product{Code: "p1", Name: "first", Price: 10}, []string{"code"}, []string{"price"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Upsert(ctx, &Product{Code: "p1", Name: "second", Price: 20}, []string{"code"}, []string{"price"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var products []Product
+
+	if err := repo.Find(ctx, &products); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(products) != 1 || products[0].Name != "first" || products[0].Price != 20 {
+		t.Fatalf("got %+v, want price updated and name preserved", products)
+	}
+
+	if err := repo.Upsert(ctx, &Product{Code: "p1", Name: "third", Price: 30}, []string{"code"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Find(ctx, &products); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(products) != 1 || products[0].Name != "first" || products[0].Price != 20 {
+		t.Fatalf("got %+v, want the conflicting record untouched", products)
+	}
+}
+
+func TestUpsertReturning(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+	product := &Product{Code: "p1", Name: "first", Price: 10}
+
+	if created, err := repo.UpsertReturning(ctx, product, []string{"code"}, []string{"price"}); err != nil || !created || product.ID == 0 {
+		t.Fatalf("got %v, %v, %+v, want a new key created", created, err, product)
+	}
+
+	if created, err := repo.UpsertReturning(ctx, &Product{Code: "p1", Price: 20}, []string{"code"}, []string{"price"}); err != nil || created {
+		t.Fatalf("got %v, %v, want an existing key updated", created, err)
+	}
+
+	if created, err := repo.UpsertReturning(ctx, &Product{Code: "p1", Price: 30}, []string{"code"}, nil); err != nil || created {
+		t.Fatalf("got %v, %v, want an existing key left untouched", created, err)
+	}
+
+	var stored Product
+
+	if err := repo.First(ctx, &stored, product.ID); err != nil || stored.Price != 20 {
+		t.Fatalf("got %+v, %v, want price 20", stored, err)
+	}
+}
+
+func TestUpsertReturningDialects(t *testing.T) {
+	ctx := context.Background()
+	db := openDialect(t, "postgres", &Product{})
+	rec := record(t, db)
+
+	if _, err := InitRepository[Product](db).UpsertReturning(ctx, &Product{Code: "p1"}, []string{"code"}, []string{"price"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if sql := rec.last(); !strings.Contains(sql, "ON CONFLICT") || !strings.HasSuffix(sql, "RETURNING `id`,(xmax = 0) AS regorm_created") {
+		t.Fatalf("got %s, want the outcome returned by the upsert", sql)
+	}
+
+	if n := rec.count("SELECT"); n != 0 {
+		t.Fatalf("got %d lookups, want none", n)
+	}
+
+	db = openDialect(t, "mysql", &Product{})
+	rec = record(t, db)
+
+	if _, err := InitRepository[Product](db).UpsertReturning(ctx, &Product{Code: "p1"}, []string{"code"}, []string{"price"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if sqls := rec.all(); len(sqls) != 1 || !strings.HasPrefix(sqls[0], "INSERT") {
+		t.Fatalf("got %v, want the upsert alone", sqls)
+	}
+}
+
+func TestBatchUpsert(t *testing.T) {
+	db := openDB(t, &Product{})
+	repo := InitRepository[Product](db)
+	ctx := context.Background()
+	seedProducts(t, repo)
+
+	products := []*Product{
+		{Code: "cheap-1", Name: "renamed", Price: 11},
+		{Code: "cheap-2", Name: "renamed", Price: 21},
+		{Code: "new-1", Name: "new", Price: 50},
+		{Code: "new-2", Name: "new", Price: 60},
+	}
+	rec := record(t, db)
+
+	if _, err := repo.BatchUpsert(ctx, products, []string{"code"}, []string{"price"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := rec.count("INSERT"); n != 1 {
+		t.Fatalf("got %d statements, want a single multi-row one", n)
+	}
+
+	var stored []Product
+
+	if err := repo.Find(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]Product{}
+
+	for _, product := range stored {
+		got[product.Code] = product
+	}
+
+	if len(stored) != 6 || got["cheap-1"].Price != 11 || got["cheap-2"].Price != 21 || got["cheap-1"].Name == "renamed" {
+		t.Fatalf("got %+v, want the prices of the existing products updated and their names preserved", stored)
+	}
+
+	if got["new-1"].Price != 50 || got["new-2"].Name != "new" {
+		t.Fatalf("got %+v, want the new products inserted", stored)
+	}
+}
+
+func TestCreateIgnore(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+
+	if _, err := repo.BatchCreate(ctx, []*Product{{Code: "p1", Name: "first"}, {Code: "p2", Name: "second"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	products := []*Product{{Code: "p1", Name: "renamed"}, {Code: "p3", Name: "third"}, {Code: "p2", Name: "renamed"}, {Code: "p4", Name: "fourth"}}
+
+	if n, err := repo.CreateIgnore(ctx, products); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2 inserted", n, err)
+	}
+
+	var stored []Product
+
+	if err := repo.Find(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]Product{}
+
+	for _, product := range stored {
+		got[product.Code] = product
+	}
+
+	if len(stored) != 4 || got["p1"].Name != "first" || got["p2"].Name != "second" || got["p3"].Name != "third" || got["p4"].Name != "fourth" {
+		t.Fatalf("got %+v, want the new products inserted and the existing ones untouched", stored)
+	}
+
+	checkIDs(t, products, got)
+
+	if n, err := repo.CreateIgnore(ctx, products); err != nil || n != 0 {
+		t.Fatalf("got %d, %v, want nothing inserted again", n, err)
+	}
+}
+
+func TestBatchUpsertDoNothing(t *testing.T) {
+	repo := InitRepository[Product](openDB(t, &Product{}))
+	ctx := context.Background()
+	seedProducts(t, repo)
+
+	products := []*Product{{Code: "cheap-1", Price: 11}, {Code: "new-1", Price: 50}, {Code: "mid", Price: 31}, {Code: "new-2", Price: 60}}
+
+	if n, err := repo.BatchUpsert(ctx, products, []string{"code"}, nil); err != nil || n != 2 {
+		t.Fatalf("got %d, %v, want 2 inserted", n, err)
+	}
+
+	var stored []Product
+
+	if err := repo.Find(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]Product{}
+
+	for _, product := range stored {
+		got[product.Code] = product
+	}
+
+	if len(stored) != 6 || got["cheap-1"].Price != 10 || got["mid"].Price != 30 {
+		t.Fatalf("got %+v, want the existing products untouched", stored)
+	}
+
+	checkIDs(t, products, got)
+}
+
+// checkIDs checks the inserted products have the id of their own record and the skipped ones a zero id,
+// a product is skipped when the record stored with its code has another name or price
+func checkIDs(t *testing.T, products []*Product, stored map[string]Product) {
+	t.Helper()
+
+	for _, product := range products {
+		record := stored[product.Code]
+		want := record.ID
+
+		if record.Name != product.Name || record.Price != product.Price {
+			want = 0
+		}
+
+		if product.ID != want {
+			t.Errorf("%s: got id %d, want %d", product.Code, product.ID, want)
+		}
+	}
+}